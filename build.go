@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/clidockermgr/docker"
+	"github.com/clidockermgr/ui"
+)
+
+func StepStyle() {
+	ui.CurrentTheme.Step.Apply()
+}
+
+/**
+	Appends the build output to a text view, remembering
+	where the current step starts to highlight it on failure.
+**/
+type BuildLog struct {
+	mutex    sync.Mutex
+	textView *ui.TextView
+	stepLine int
+	partial  string
+}
+
+func (b *BuildLog) Update(message docker.ProgressMessage) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var text = message.Stream
+	if text == "" {
+		text = message.Status
+		if text != "" {
+			text += "\n"
+		}
+	}
+
+	// output may come split at any point, only complete lines are shown
+	var lines = strings.Split(b.partial+text, "\n")
+	b.partial = lines[len(lines)-1]
+
+	for _, line := range lines[0 : len(lines)-1] {
+		if strings.HasPrefix(line, "Step ") {
+			b.stepLine = b.textView.LineCount()
+			b.textView.AppendStyled(line, StepStyle)
+		} else {
+			b.textView.Append(line)
+		}
+	}
+}
+
+func (b *BuildLog) Failed(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.partial != "" {
+		b.textView.Append(b.partial)
+	}
+	b.textView.SetLineStyle(b.stepLine, ui.ErrorStyle)
+	b.textView.AppendStyled(err.Error(), ui.ErrorStyle)
+}
+
+// Parses build arguments given like "KEY=VALUE OTHER=VALUE"
+func ParseBuildArgs(text string) map[string]*string {
+	var args = make(map[string]*string)
+
+	for _, arg := range strings.Fields(text) {
+		var parts = strings.SplitN(arg, "=", 2)
+		if len(parts) == 2 {
+			args[parts[0]] = &parts[1]
+		} else {
+			// like docker build, a name alone takes the value from the environment
+			value, ok := os.LookupEnv(parts[0])
+			if ok {
+				args[parts[0]] = &value
+			}
+		}
+	}
+	return args
+}
+
+var BuildContextHistory = ui.InputHistoryNew()
+var BuildTagsHistory = ui.InputHistoryNew()
+var BuildArgsHistory = ui.InputHistoryNew()
+
+func ValidateDirectory(value interface{}) error {
+	info, err := os.Stat(value.(string))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New("not a directory")
+	}
+	return nil
+}
+
+func ShowBuildImage(app *ui.Application, client *docker.ServiceHandler) {
+	workDir, _ := os.Getwd()
+
+	var form = ui.FormNew()
+	form.AddTextField("context", "Context directory", workDir, BuildContextHistory, ValidateDirectory)
+	form.AddTextField("dockerfile", "Dockerfile", "Dockerfile", nil, ui.RequiredValidator)
+	form.AddTextField("tags", "Tags", "", BuildTagsHistory, nil)
+	form.AddTextField("args", "Build arguments", "", BuildArgsHistory, nil)
+	form.AddCheckbox("nocache", "Do not use cache", false)
+	form.AddCheckbox("pull", "Pull base images", false)
+
+	app.ShowDialog(ui.DialogNew("Build image", form, 80, func(result ui.FormResult) {
+		RunBuildImage(app, client, docker.BuildOptions{
+			ContextDir: result.String("context"),
+			Dockerfile: result.String("dockerfile"),
+			Tags:       strings.Fields(strings.ReplaceAll(result.String("tags"), ",", " ")),
+			BuildArgs:  ParseBuildArgs(result.String("args")),
+			NoCache:    result.Bool("nocache"),
+			PullParent: result.Bool("pull"),
+		})
+	}))
+}
+
+func RunBuildImage(app *ui.Application, client *docker.ServiceHandler, options docker.BuildOptions) {
+	var textView = ui.TextViewNew("")
+	textView.SetFollow(true)
+
+	var title = "Building " + options.ContextDir
+	if len(options.Tags) > 0 {
+		title = "Building " + strings.Join(options.Tags, ", ")
+	}
+
+	var popup = MakeTextPopup(title, textView)
+	var buildLog = BuildLog{textView: textView, stepLine: -1}
+
+	ShowTextContainer(app, popup, nil)
+	app.ShowMessage(title + "...")
+
+	go func() {
+		err := client.BuildImage(options, buildLog.Update)
+
+		if err != nil {
+			buildLog.Failed(err)
+			popup.SetTitle(title + " - failed")
+			app.ShowError(fmt.Sprintf("%s failed: %s", title, err))
+		} else {
+			popup.SetTitle(title + " - done")
+			app.ShowMessage(title + " done")
+		}
+	}()
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+/**
+	Settings read from the config file, like:
+
+	theme: light
+	colors: 256
+	keys:
+	  container.logs: l
+	  container.delete: [delete, ctrl+d]
+**/
+type Config struct {
+	// A built-in theme name or the path of a theme file
+	Theme string `yaml:"theme"`
+	// 16, 256 or truecolor, guessed from the terminal when empty
+	Colors string             `yaml:"colors"`
+	Keys   map[string]KeyList `yaml:"keys"`
+}
+
+/**
+	One or more key names, written either as a single string or a list
+**/
+type KeyList []string
+
+func (k *KeyList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*k = KeyList{single}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*k = KeyList(list)
+	return nil
+}
+
+// The default config file, ~/.config/clidockermgr/config.yaml on linux
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.yaml"
+	}
+	return filepath.Join(dir, "clidockermgr", "config.yaml")
+}
+
+/**
+	Reads the config file, a missing file gives an empty config
+**/
+func Load(path string) (*Config, error) {
+	var config = Config{}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Key bindings in the form taken by Keymap.RemapNames
+func (c *Config) KeyBindings() map[string][]string {
+	var bindings = make(map[string][]string)
+	for name, keys := range c.Keys {
+		bindings[name] = keys
+	}
+	return bindings
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/clidockermgr/docker"
+	"github.com/clidockermgr/ui"
+)
+
+// The context the views are connected to, and its service
+var currentContext docker.DockerContext
+var currentService *docker.ServiceHandler
+
+/**
+	Runs the docker CLI against the current context
+**/
+func DockerCommand(app *ui.Application, args ...string) {
+	RunCommand(app, "docker", append([]string{"--context", currentContext.Name}, args...)...)
+}
+
+func ContextHeaderText(dockerContext docker.DockerContext) string {
+	return fmt.Sprintf("Context: %s (%s)", dockerContext.Name, dockerContext.Host)
+}
+
+/**
+	Builds the views of the main screen, connected to the service
+**/
+func BuildMainLayout(app *ui.Application, service *docker.ServiceHandler) ui.Layout {
+	var header = ui.LabelNew(ContextHeaderText(currentContext))
+	header.Style = ui.HeaderStyle
+
+	var statusBar = ui.LabelNew("Press " + app.Keymap().Action("app.help").KeyNames() + " for help")
+	app.SetStatusBar(statusBar)
+
+	containersView, containerList := BuildContainersView(app, service)
+	return ui.VBoxNew().
+		Add(header, ui.Fixed(1)).
+		Add(containersView, ui.Flex(1)).
+		Add(BuildImagesView(app, service), ui.Flex(1)).
+		Add(BuildVolumesView(app, service), ui.Flex(1)).
+		Add(BuildNetworksView(app, service, containerList), ui.Flex(1)).
+		Add(statusBar, ui.Fixed(1))
+}
+
+/**
+	Connects to the daemon of the context, replacing the service
+	and building all the views again against the new one
+**/
+func Connect(app *ui.Application, dockerContext docker.DockerContext) error {
+	client, err := dockerContext.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if currentService != nil {
+		currentService.Close()
+	}
+	var service = docker.ServiceHandlerNew(client)
+	service.SetStopTimeout(*stopTimeout)
+	currentService = service
+	currentContext = dockerContext
+
+	app.Clear()
+	app.SetRootLayout(BuildMainLayout(app, service))
+
+	go func() {
+		if err := service.Ping(); err != nil {
+			log.Print("Error connecting to context ", dockerContext.Name, ": ", err)
+			if service == currentService {
+				app.ShowError(fmt.Sprintf("Connecting to %s failed: %s", dockerContext.Host, err))
+			}
+		}
+	}()
+	return nil
+}
+
+func ShowContextPicker(app *ui.Application) {
+	contexts, err := docker.ListContexts()
+	if err != nil {
+		log.Print("Error listing contexts ", err)
+	}
+
+	var names = make([]string, len(contexts))
+	for i := range contexts {
+		names[i] = contexts[i].Name
+	}
+
+	ShowPicker(app, "Docker context, now "+currentContext.Name, names, func(name string) {
+		app.ClosePopup()
+
+		dockerContext, err := docker.FindContext(name)
+		if err == nil {
+			err = Connect(app, dockerContext)
+		}
+		if err != nil {
+			app.ShowError(fmt.Sprintf("Switching to context %s failed: %s", name, err))
+			return
+		}
+		app.Resize()
+		app.ShowMessage("Switched to context " + name)
+	})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/clidockermgr/docker"
+	"github.com/clidockermgr/ui"
+	"github.com/clidockermgr/util"
+	"github.com/docker/docker/api/types"
+)
+
+/**
+	Charts of the recent stats of a container, refreshed
+	every stats interval while the popup is open
+**/
+type Dashboard struct {
+	ui.ViewImpl
+	client      *docker.ServiceHandler
+	containerId string
+	layout      *ui.Box
+	cpu         *ui.Sparkline
+	memory      *ui.Sparkline
+	netRx       *ui.Sparkline
+	netTx       *ui.Sparkline
+	blockRead   *ui.Sparkline
+	blockWrite  *ui.Sparkline
+	stop        chan bool
+}
+
+func DashboardNew(client *docker.ServiceHandler, containerId string) *Dashboard {
+	var dashboard = Dashboard{
+		client:      client,
+		containerId: containerId,
+		cpu:         ui.SparklineNew("CPU"),
+		memory:      ui.SparklineNew("Memory"),
+		netRx:       ui.SparklineNew("Network received"),
+		netTx:       ui.SparklineNew("Network transmitted"),
+		blockRead:   ui.SparklineNew("Block read"),
+		blockWrite:  ui.SparklineNew("Block written"),
+	}
+	dashboard.Init()
+
+	// The rates are charted side by side, received and read on the left
+	var rates = ui.GridNew([]ui.Size{ui.Flex(1), ui.Flex(1)}, []ui.Size{ui.Percent(50), ui.Flex(1)}).
+		Add(dashboard.netRx, 0, 0).
+		Add(dashboard.netTx, 0, 1).
+		Add(dashboard.blockRead, 1, 0).
+		Add(dashboard.blockWrite, 1, 1)
+
+	dashboard.layout = ui.VBoxNew().
+		Add(dashboard.cpu, ui.Percent(25)).
+		Add(dashboard.memory, ui.Percent(25)).
+		Add(rates, ui.Flex(1))
+	return &dashboard
+}
+
+func (d *Dashboard) charts() []*ui.Sparkline {
+	return []*ui.Sparkline{d.cpu, d.memory, d.netRx, d.netTx, d.blockRead, d.blockWrite}
+}
+
+func (d *Dashboard) SetRect(rect ui.Rect) {
+	d.ViewImpl.SetRect(rect)
+	d.layout.SetRect(rect)
+}
+
+func (d *Dashboard) Draw() {
+	for _, chart := range d.charts() {
+		chart.Draw()
+	}
+}
+
+func (d *Dashboard) CheckRedrawFlag() bool {
+	var redraw = d.ViewImpl.CheckRedrawFlag()
+	for _, chart := range d.charts() {
+		redraw = chart.CheckRedrawFlag() || redraw
+	}
+	return redraw
+}
+
+func (d *Dashboard) Start() {
+	d.stop = make(chan bool)
+	d.Update()
+
+	go func() {
+		var ticker = time.NewTicker(docker.StatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.Update()
+			}
+		}
+	}()
+}
+
+func (d *Dashboard) Stop() {
+	close(d.stop)
+}
+
+func RateTitle(title string, values []float64) string {
+	if len(values) == 0 {
+		return title
+	}
+	return fmt.Sprintf("%s: %s now, %s peak", title,
+		util.FormatRate(values[len(values)-1]), util.FormatRate(ui.SparklineMax(values, 0)))
+}
+
+/**
+	Charts are scaled to their highest sample, titles
+	give the latest value and the peak
+**/
+func (d *Dashboard) Update() {
+	var samples = d.client.History(d.containerId)
+
+	var cpu = docker.CPUValues(samples)
+	var cpuTitle = "CPU: no samples, the container is not running"
+	if len(cpu) > 0 {
+		cpuTitle = fmt.Sprintf("CPU: %s now, %s peak", util.FormatPercent(cpu[len(cpu)-1]), util.FormatPercent(ui.SparklineMax(cpu, 0)))
+	}
+	d.cpu.SetValues(cpuTitle, cpu, ui.SparklineMax(cpu, docker.CPUSparklineFloor))
+
+	var memory = docker.MemoryValues(samples)
+	var memoryTitle = "Memory"
+	if len(samples) > 0 {
+		var last = samples[len(samples)-1]
+		memoryTitle = fmt.Sprintf("Memory: %s of %s now, %s peak", util.FormatMemory(last.MemoryUsage),
+			util.FormatMemory(last.MemoryLimit), util.FormatMemory(uint64(ui.SparklineMax(memory, 0))))
+	}
+	d.memory.SetValues(memoryTitle, memory, ui.SparklineMax(memory, 0))
+
+	var netRx = docker.RateValues(samples, func(rates docker.IORates) float64 { return rates.NetRx })
+	var netTx = docker.RateValues(samples, func(rates docker.IORates) float64 { return rates.NetTx })
+	var blockRead = docker.RateValues(samples, func(rates docker.IORates) float64 { return rates.BlockRead })
+	var blockWrite = docker.RateValues(samples, func(rates docker.IORates) float64 { return rates.BlockWrite })
+
+	d.netRx.SetValues(RateTitle("Network received", netRx), netRx, ui.SparklineMax(netRx, 1))
+	d.netTx.SetValues(RateTitle("Network transmitted", netTx), netTx, ui.SparklineMax(netTx, 1))
+	d.blockRead.SetValues(RateTitle("Block read", blockRead), blockRead, ui.SparklineMax(blockRead, 1))
+	d.blockWrite.SetValues(RateTitle("Block written", blockWrite), blockWrite, ui.SparklineMax(blockWrite, 1))
+}
+
+func ShowDashboard(app *ui.Application, client *docker.ServiceHandler, container *types.Container) {
+	var dashboard = DashboardNew(client, container.ID)
+	var popup = ui.TitledContainerNew(fmt.Sprintf("Dashboard of %s, last %s", ContainerName(container),
+		time.Duration(docker.StatsHistoryLength)*docker.StatsInterval), dashboard, true)
+	popup.Border = ui.LineBorder
+
+	dashboard.Start()
+	ShowTextContainer(app, popup, dashboard.Stop)
+}
@@ -0,0 +1,262 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+/**
+	Options for building an image, the Dockerfile
+	path is relative to the context directory
+**/
+type BuildOptions struct {
+	ContextDir string
+	Dockerfile string
+	Tags       []string
+	BuildArgs  map[string]*string
+	NoCache    bool
+	PullParent bool
+}
+
+type ignorePattern struct {
+	expression *regexp.Regexp
+	exclusion  bool
+}
+
+/**
+	Matches paths against the patterns of a .dockerignore file,
+	the last matching pattern decides whether a path is ignored.
+**/
+type DockerIgnore struct {
+	patterns      []ignorePattern
+	hasExclusions bool
+}
+
+func DockerIgnoreNew(lines []string) (*DockerIgnore, error) {
+	var ignore = DockerIgnore{}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var pattern = ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			pattern.exclusion = true
+			ignore.hasExclusions = true
+			line = strings.TrimSpace(line[1:])
+		}
+
+		line = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(line)), "/")
+
+		expression, err := regexp.Compile(PatternExpression(line))
+		if err != nil {
+			return nil, err
+		}
+		pattern.expression = expression
+		ignore.patterns = append(ignore.patterns, pattern)
+	}
+	return &ignore, nil
+}
+
+// Translates a .dockerignore pattern into a regular expression
+func PatternExpression(pattern string) string {
+	var expression = "^"
+
+	for i := 0; i < len(pattern); i++ {
+		var char = pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression += "(.*/)?"
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression += ".*"
+			i++
+		case char == '*':
+			expression += "[^/]*"
+		case char == '?':
+			expression += "[^/]"
+		case char == '\\' && i+1 < len(pattern):
+			i++
+			expression += regexp.QuoteMeta(string(pattern[i]))
+		default:
+			expression += regexp.QuoteMeta(string(char))
+		}
+	}
+	// matching a directory also matches everything below it
+	return expression + "(/.*)?$"
+}
+
+func ReadDockerIgnore(contextDir string) (*DockerIgnore, error) {
+	file, err := os.Open(filepath.Join(contextDir, ".dockerignore"))
+
+	if os.IsNotExist(err) {
+		return DockerIgnoreNew(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	var scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return DockerIgnoreNew(lines)
+}
+
+func (d *DockerIgnore) Ignores(path string) bool {
+	var ignored = false
+	for _, pattern := range d.patterns {
+		if pattern.expression.MatchString(path) {
+			ignored = !pattern.exclusion
+		}
+	}
+	return ignored
+}
+
+/**
+	Writes the context directory as a tar stream, skipping what
+	.dockerignore excludes. The Dockerfile and .dockerignore are
+	always sent, as the daemon needs them.
+**/
+func TarContext(contextDir string, dockerfile string, writer io.Writer) error {
+	ignore, err := ReadDockerIgnore(contextDir)
+	if err != nil {
+		return err
+	}
+
+	var tarWriter = tar.NewWriter(writer)
+
+	err = filepath.Walk(contextDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(contextDir, path)
+		if err != nil || relative == "." {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+
+		if relative != dockerfile && relative != ".dockerignore" && ignore.Ignores(relative) {
+			// excluded directories can only be skipped when nothing below may be included
+			// back, and the directories holding the Dockerfile never are
+			if info.IsDir() && !ignore.hasExclusions && !strings.HasPrefix(dockerfile, relative+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var link = ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = relative
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+	return tarWriter.Close()
+}
+
+/**
+	Builds an image, the output is passed to the handler as
+	it arrives. Blocks until the build is finished.
+**/
+func (s *ServiceHandler) BuildImage(options BuildOptions, handler ProgressHandler) error {
+	var err = s.DoBuildImage(options, handler)
+
+	if err != nil {
+		log.Print("Error building image", err)
+	}
+	s.RefreshImages()
+	return err
+}
+
+func (s *ServiceHandler) DoBuildImage(options BuildOptions, handler ProgressHandler) error {
+	var dockerfile = options.Dockerfile
+
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if filepath.IsAbs(dockerfile) {
+		relative, err := filepath.Rel(options.ContextDir, dockerfile)
+		if err != nil {
+			return err
+		}
+		dockerfile = relative
+	}
+	dockerfile = filepath.ToSlash(filepath.Clean(dockerfile))
+
+	if strings.HasPrefix(dockerfile, "../") {
+		return errors.New("the Dockerfile must be inside the context directory")
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(TarContext(options.ContextDir, dockerfile, writer))
+	}()
+
+	response, err := s.client.ImageBuild(context.Background(), reader, types.ImageBuildOptions{
+		Tags:       options.Tags,
+		Dockerfile: dockerfile,
+		BuildArgs:  options.BuildArgs,
+		NoCache:    options.NoCache,
+		PullParent: options.PullParent,
+		Remove:     true,
+	})
+
+	if err != nil {
+		reader.CloseWithError(err)
+		return err
+	}
+	defer response.Body.Close()
+
+	return ReadProgress(response.Body, handler)
+}
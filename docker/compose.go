@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+)
+
+const (
+	ComposeProjectLabel = "com.docker.compose.project"
+	ComposeServiceLabel = "com.docker.compose.service"
+)
+
+/**
+	The containers of a docker compose project
+**/
+type ComposeProject struct {
+	Name       string
+	Containers []types.Container
+	summaries  []ContainerSummary
+}
+
+func (p *ComposeProject) Running() int {
+	var running = 0
+	for i := range p.Containers {
+		if p.Containers[i].State == "running" {
+			running++
+		}
+	}
+	return running
+}
+
+func (p *ComposeProject) Status() string {
+	return fmt.Sprintf("%d/%d running", p.Running(), len(p.Containers))
+}
+
+func ComposeService(container *types.Container) string {
+	return container.Labels[ComposeServiceLabel]
+}
+
+/**
+	Groups containers by compose project, sorted by name. Containers
+	keep their order within each project, containers not started by
+	compose are returned apart.
+**/
+func GroupContainers(containers []ContainerSummary) ([]ComposeProject, []ContainerSummary) {
+	var byName = make(map[string][]ContainerSummary)
+	var ungrouped []ContainerSummary
+
+	for i := range containers {
+		var name = containers[i].container.Labels[ComposeProjectLabel]
+		if name == "" {
+			ungrouped = append(ungrouped, containers[i])
+		} else {
+			byName[name] = append(byName[name], containers[i])
+		}
+	}
+
+	var projects = make([]ComposeProject, 0, len(byName))
+	for name, summaries := range byName {
+		var project = ComposeProject{Name: name, summaries: summaries}
+		for i := range summaries {
+			project.Containers = append(project.Containers, summaries[i].container)
+		}
+		projects = append(projects, project)
+	}
+	sort.Slice(projects, func(a, b int) bool {
+		return projects[a].Name < projects[b].Name
+	})
+	return projects, ungrouped
+}
@@ -3,6 +3,7 @@ package docker
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/clidockermgr/ui"
 	"github.com/clidockermgr/util"
@@ -11,25 +12,42 @@ import (
 )
 
 const (
-	ContainerListModelOnlyActive = 1
+	ContainerListModelOnlyActive      = 1
+	ContainerListModelGrouped         = 2
+	ContainerListModelToggleCollapsed = 3
+	ContainerListModelSortColumn      = 4
+	ContainerListModelSortOrder       = 5
+	ContainerListModelShowIO          = 6
 )
 
+// CPU percentage taking the whole height of sparklines, so that idle noise stays low
+const CPUSparklineFloor = 1
+
 type ContainerListModelItem struct {
-	container types.Container
-	usedMem   uint64
-	maxMem    uint64
-	diskUsage int64
+	container  types.Container
+	cpuPercent float64
+	usedMem    uint64
+	maxMem     uint64
+	diskUsage  int64
+	ioRates    IORates
+	recent     []StatsSample
+	showIO     bool
+	grouped    bool
 }
 
 func (c ContainerListModelItem) Value() interface{} {
 	return &c.container
 }
 
+func (c ContainerListModelItem) Key() string {
+	return c.container.ID
+}
+
 func (i ContainerListModelItem) String() string {
 
 	var image = i.container.Image
 
-	if image[0:6] == "sha256" {
+	if strings.HasPrefix(image, "sha256:") {
 		image = image[7:19]
 	}
 
@@ -45,24 +63,91 @@ func (i ContainerListModelItem) String() string {
 
 	var status = i.container.Status
 
+	var name = ""
+	if len(i.container.Names) > 0 {
+		name = strings.TrimPrefix(i.container.Names[0], "/")
+	}
+
+	if len(name) > 25 {
+		name = name[0:22] + "..."
+	}
+
 	var memString = fmt.Sprintf("%s / %s", util.FormatMemory(i.usedMem), util.FormatMemory(i.maxMem))
 
 	var diskString = util.FormatMemory(uint64(i.diskUsage))
 
-	return fmt.Sprintf("%s %-40s %-30s %-30s %20s %10s", i.container.ID[0:12], image, command, status, memString, diskString)
+	var cpuString = "-"
+	if i.container.State == "running" {
+		cpuString = util.FormatPercent(i.cpuPercent)
+	}
+
+	// Trends of the last samples, scaled to their highest value
+	var cpuValues = CPUValues(i.recent)
+	var memoryValues = MemoryValues(i.recent)
+	cpuString += " " + ui.SparklineString(cpuValues, RecentSamples, ui.SparklineMax(cpuValues, CPUSparklineFloor))
+	memString += " " + ui.SparklineString(memoryValues, RecentSamples, ui.SparklineMax(memoryValues, 0))
+
+	// Usage comes right after the name so that the trends fit narrow terminals
+	if i.showIO {
+		// The rates take the place of the command and the disk usage
+		var ioString = fmt.Sprintf("net rx %12s tx %12s  blk r %12s w %12s",
+			util.FormatRate(i.ioRates.NetRx), util.FormatRate(i.ioRates.NetTx),
+			util.FormatRate(i.ioRates.BlockRead), util.FormatRate(i.ioRates.BlockWrite))
+
+		if i.grouped {
+			return fmt.Sprintf("    %-20s %s %18s %31s  %s  %-25s %-40s %s", ComposeService(&i.container), i.container.ID[0:12], cpuString, memString, ioString, name, image, status)
+		}
+		return fmt.Sprintf("%s %-25s %18s %31s  %s  %-40s %s", i.container.ID[0:12], name, cpuString, memString, ioString, image, status)
+	}
+
+	if i.grouped {
+		return fmt.Sprintf("    %-20s %s %18s %31s %-25s %-40s %-30s %10s", ComposeService(&i.container), i.container.ID[0:12], cpuString, memString, name, image, status, diskString)
+	}
+
+	return fmt.Sprintf("%s %-25s %18s %31s %-40s %-30s %-30s %10s", i.container.ID[0:12], name, cpuString, memString, image, command, status, diskString)
+}
+
+/**
+	The header row of a compose project in the grouped containers list
+**/
+type ProjectItem struct {
+	project   ComposeProject
+	collapsed bool
+}
+
+func (i ProjectItem) Value() interface{} {
+	return &i.project
+}
+
+func (i ProjectItem) Key() string {
+	return "project " + i.project.Name
+}
+
+func (i ProjectItem) String() string {
+	var marker = "-"
+	if i.collapsed {
+		marker = "+"
+	}
+	return fmt.Sprintf("%s %-30s %s", marker, i.project.Name, i.project.Status())
 }
 
 type ContainerListModel struct {
 	ui.BaseListModel
 	dockerClient *ServiceHandler
 	items        []ContainerSummary
+	rows         []ui.ListItem
 	onlyActive   bool
 	active       bool
+	grouped      bool
+	collapsed    map[string]bool
+	sortColumn   int
+	descending   bool
+	showIO       bool
 }
 
 func ContainerListModelNew(client *ServiceHandler) *ContainerListModel {
 
-	var model = ContainerListModel{dockerClient: client, active: true}
+	var model = ContainerListModel{dockerClient: client, active: true, collapsed: make(map[string]bool)}
 	model.Init()
 	model.Update()
 	client.AddListener(&model)
@@ -74,29 +159,106 @@ func (m *ContainerListModel) SetProperty(property int, value interface{}) {
 	case ContainerListModelOnlyActive:
 		m.onlyActive = !m.onlyActive
 		m.Update()
+	case ContainerListModelGrouped:
+		m.grouped = !m.grouped
+		m.Update()
+	case ContainerListModelToggleCollapsed:
+		var project = value.(string)
+		m.collapsed[project] = !m.collapsed[project]
+		m.Update()
+	case ContainerListModelSortColumn:
+		m.sortColumn = (m.sortColumn + 1) % len(ContainerSortColumns)
+		m.Update()
+	case ContainerListModelSortOrder:
+		m.descending = !m.descending
+		m.Update()
+	case ContainerListModelShowIO:
+		m.showIO = !m.showIO
+		m.Update()
 	}
 }
 
+func (m *ContainerListModel) SortDescription() string {
+	return SortDescription(ContainerSortColumns[m.sortColumn].Name, m.descending)
+}
+
+func (m *ContainerListModel) Grouped() bool {
+	return m.grouped
+}
+
+func (m *ContainerListModel) MakeItem(summary ContainerSummary, grouped bool) *ContainerListModelItem {
+	return &ContainerListModelItem{
+		summary.container,
+		util.CPUPercent(&summary.stats.Stats),
+		summary.stats.MemoryStats.Usage,
+		summary.stats.MemoryStats.Limit,
+		summary.diskUsage,
+		summary.ioRates,
+		summary.recent,
+		m.showIO,
+		grouped,
+	}
+}
+
+func (m *ContainerListModel) Shows(summary ContainerSummary) bool {
+	return !m.onlyActive || summary.container.State == "running"
+}
+
+/**
+	Builds the rows, when grouped each compose project gets a header
+	followed by its containers unless collapsed, then come the
+	containers not started by compose. Projects are grouped before
+	hiding stopped containers, so that their status counts them all.
+**/
 func (m *ContainerListModel) Update() {
 	m.items = m.dockerClient.Containers()
+	SortContainers(m.items, m.sortColumn, m.descending)
+
+	var rows []ui.ListItem
+	var ungrouped = m.items
+
+	if m.grouped {
+		var projects []ComposeProject
+		projects, ungrouped = GroupContainers(m.items)
+
+		for _, project := range projects {
+			if m.onlyActive && project.Running() == 0 {
+				continue
+			}
+			var collapsed = m.collapsed[project.Name]
+			rows = append(rows, &ProjectItem{project: project, collapsed: collapsed})
+			if !collapsed {
+				for i := range project.summaries {
+					if m.Shows(project.summaries[i]) {
+						rows = append(rows, m.MakeItem(project.summaries[i], true))
+					}
+				}
+			}
+		}
+	}
+	for i := range ungrouped {
+		if m.Shows(ungrouped[i]) {
+			rows = append(rows, m.MakeItem(ungrouped[i], false))
+		}
+	}
+
+	m.rows = rows
 	m.NotifyChanged()
 }
 
 func (m ContainerListModel) ItemCount() int {
-	return len(m.items)
+	return len(m.rows)
 }
 
 func (m ContainerListModel) Item(index int) ui.ListItem {
-
-	return &ContainerListModelItem{
-		m.items[index].container,
-		m.items[index].stats.MemoryStats.Usage,
-		m.items[index].stats.MemoryStats.Limit,
-		m.items[index].diskUsage,
-	}
+	return m.rows[index]
 }
 func (m *ContainerListModel) ImagesUpdated() {
 }
+func (m *ContainerListModel) NetworksUpdated() {
+}
+func (m *ContainerListModel) VolumesUpdated() {
+}
 func (m *ContainerListModel) ContainersUpdated() {
 	m.Update()
 	log.Printf("Model changed %d", len(m.items))
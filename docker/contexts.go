@@ -0,0 +1,241 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/docker/client"
+)
+
+const DefaultContextName = "default"
+
+/**
+	A docker CLI context, the endpoint of a docker daemon
+	with the TLS material needed to connect to it
+**/
+type DockerContext struct {
+	Name          string
+	Description   string
+	Host          string
+	SkipTLSVerify bool
+	TLSDir        string
+}
+
+type contextMeta struct {
+	Name      string
+	Metadata  map[string]interface{}
+	Endpoints map[string]struct {
+		Host          string
+		SkipTLSVerify bool
+	}
+}
+
+// The docker CLI config dir, ~/.docker unless DOCKER_CONFIG is set
+func ConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker")
+}
+
+// Contexts are stored in directories named after the digest of their names
+func contextDirName(name string) string {
+	var digest = sha256.Sum256([]byte(name))
+	return hex.EncodeToString(digest[:])
+}
+
+/**
+	The default context, which takes its endpoint from the environment
+**/
+func DefaultContext() DockerContext {
+	var host = os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = client.DefaultDockerHost
+	}
+	return DockerContext{
+		Name:        DefaultContextName,
+		Description: "Current DOCKER_HOST based configuration",
+		Host:        host,
+	}
+}
+
+/**
+	Lists the contexts known by the docker CLI, the default one first
+**/
+func ListContexts() ([]DockerContext, error) {
+	var contexts = []DockerContext{DefaultContext()}
+
+	var metaDir = filepath.Join(ConfigDir(), "contexts", "meta")
+	entries, err := ioutil.ReadDir(metaDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return contexts, nil
+	}
+	if err != nil {
+		return contexts, err
+	}
+
+	var found []DockerContext
+	for _, entry := range entries {
+		data, err := ioutil.ReadFile(filepath.Join(metaDir, entry.Name(), "meta.json"))
+		if err != nil {
+			continue
+		}
+		var meta contextMeta
+		if err = json.Unmarshal(data, &meta); err != nil {
+			log.Print("Error reading context ", entry.Name(), ": ", err)
+			continue
+		}
+		found = append(found, MakeContext(meta))
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Name < found[j].Name
+	})
+	return append(contexts, found...), nil
+}
+
+func MakeContext(meta contextMeta) DockerContext {
+	var dockerContext = DockerContext{Name: meta.Name}
+
+	if description, ok := meta.Metadata["Description"].(string); ok {
+		dockerContext.Description = description
+	}
+	if endpoint, ok := meta.Endpoints["docker"]; ok {
+		dockerContext.Host = endpoint.Host
+		dockerContext.SkipTLSVerify = endpoint.SkipTLSVerify
+	}
+
+	var tlsDir = filepath.Join(ConfigDir(), "contexts", "tls", contextDirName(meta.Name), "docker")
+	if _, err := os.Stat(tlsDir); err == nil {
+		dockerContext.TLSDir = tlsDir
+	}
+	return dockerContext
+}
+
+/**
+	The context the docker CLI would use: DOCKER_HOST means the
+	default one, then DOCKER_CONTEXT, then the current context
+	in the CLI config file.
+**/
+func CurrentContextName() string {
+	if os.Getenv("DOCKER_HOST") != "" {
+		return DefaultContextName
+	}
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(ConfigDir(), "config.json"))
+	if err != nil {
+		return DefaultContextName
+	}
+	var config struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if json.Unmarshal(data, &config) != nil || config.CurrentContext == "" {
+		return DefaultContextName
+	}
+	return config.CurrentContext
+}
+
+func FindContext(name string) (DockerContext, error) {
+	contexts, err := ListContexts()
+	for i := range contexts {
+		if contexts[i].Name == name {
+			return contexts[i], nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("context %s not found", name)
+	}
+	return DockerContext{}, err
+}
+
+/**
+	Creates a client for the context endpoint
+**/
+func (c DockerContext) NewClient() (*client.Client, error) {
+	if c.Name == DefaultContextName {
+		return client.NewClientWithOpts(client.FromEnv)
+	}
+	if c.Host == "" {
+		return nil, fmt.Errorf("context %s has no docker endpoint", c.Name)
+	}
+
+	endpoint, err := url.Parse(c.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Scheme == "ssh" {
+		return client.NewClientWithOpts(
+			client.WithHost(SSHClientHost),
+			client.WithDialContext(SSHDialer(endpoint)),
+			client.WithAPIVersionNegotiation())
+	}
+
+	var options []client.Opt
+
+	if c.TLSDir != "" || c.SkipTLSVerify {
+		tlsConfig, err := c.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, client.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		}))
+	}
+
+	options = append(options, client.WithHost(c.Host), client.WithAPIVersionNegotiation())
+	return client.NewClientWithOpts(options...)
+}
+
+func (c DockerContext) TLSConfig() (*tls.Config, error) {
+	var config = tls.Config{
+		InsecureSkipVerify: c.SkipTLSVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if c.TLSDir == "" {
+		return &config, nil
+	}
+
+	ca, err := ioutil.ReadFile(filepath.Join(c.TLSDir, "ca.pem"))
+	if err == nil {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", filepath.Join(c.TLSDir, "ca.pem"))
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var certFile = filepath.Join(c.TLSDir, "cert.pem")
+	var keyFile = filepath.Join(c.TLSDir, "key.pem")
+	if _, err := os.Stat(certFile); err == nil {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return &config, nil
+}
+
+func (c DockerContext) String() string {
+	if c.Description != "" {
+		return fmt.Sprintf("%-20s %-40s %s", c.Name, c.Host, c.Description)
+	}
+	return fmt.Sprintf("%-20s %s", c.Name, c.Host)
+}
@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+)
+
+/**
+	Subscribes to the docker events stream and updates only
+	the objects affected by each event. Whenever the stream is
+	(re)connected a full resync is done, since events may have
+	been missed while disconnected.
+**/
+func (s *ServiceHandler) WatchEvents() {
+	for s.active {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.mutex.Lock()
+		s.cancelEvents = cancel
+		s.mutex.Unlock()
+
+		messages, errs := s.client.Events(ctx, types.EventsOptions{})
+
+		s.Resync()
+		s.ReadEvents(messages, errs)
+
+		cancel()
+		time.Sleep(time.Second)
+	}
+}
+
+func (s *ServiceHandler) ReadEvents(messages <-chan events.Message, errs <-chan error) {
+	for s.active {
+		select {
+		case message := <-messages:
+			s.HandleEvent(message)
+		case err := <-errs:
+			log.Printf("Error reading docker events: %s", err)
+			return
+		}
+	}
+}
+
+func (s *ServiceHandler) HandleEvent(message events.Message) {
+	switch message.Type {
+	case events.ContainerEventType:
+		s.HandleContainerEvent(message)
+	case events.ImageEventType:
+		s.HandleImageEvent(message)
+	case events.VolumeEventType:
+		s.HandleVolumeEvent(message)
+	case events.NetworkEventType:
+		s.HandleNetworkEvent(message)
+	}
+}
+
+func (s *ServiceHandler) HandleContainerEvent(message events.Message) {
+	var action = message.Action
+
+	// exec events come with the command appended, like "exec_start: sh"
+	if strings.HasPrefix(action, "exec_") || action == "top" || action == "attach" || action == "resize" {
+		return
+	}
+
+	if action == "destroy" {
+		s.DropContainer(message.Actor.ID)
+		// volumes reference counts may have changed
+		s.RefreshDiskUsage()
+		return
+	}
+
+	s.RefreshContainer(message.Actor.ID)
+
+	switch action {
+	case "create", "die", "commit", "copy":
+		s.RefreshDiskUsage()
+	}
+}
+
+func (s *ServiceHandler) HandleImageEvent(message events.Message) {
+	switch message.Action {
+	case "delete":
+		s.DropImage(message.Actor.ID)
+	default:
+		s.RefreshImages()
+	}
+}
+
+func (s *ServiceHandler) HandleVolumeEvent(message events.Message) {
+	switch message.Action {
+	case "mount", "unmount":
+		s.RefreshContainer(message.Actor.Attributes["container"])
+	default:
+		s.RefreshDiskUsage()
+	}
+}
+
+func (s *ServiceHandler) HandleNetworkEvent(message events.Message) {
+	switch message.Action {
+	case "connect", "disconnect":
+		s.RefreshContainer(message.Actor.Attributes["container"])
+	default:
+		s.RefreshNetworks()
+	}
+}
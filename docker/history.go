@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"time"
+)
+
+// Samples drawn inline in the containers list
+const RecentSamples = 10
+
+/**
+	What is kept of each stats sample for the history of a container
+**/
+type StatsSample struct {
+	Time        time.Time
+	CPUPercent  float64
+	MemoryUsage uint64
+	MemoryLimit uint64
+	IO          IORates
+}
+
+/**
+	A bounded history of samples, once full the
+	oldest sample is overwritten by each new one
+**/
+type StatsRing struct {
+	samples []StatsSample
+	next    int
+	count   int
+}
+
+func StatsRingNew(capacity int) *StatsRing {
+	return &StatsRing{samples: make([]StatsSample, capacity)}
+}
+
+func (r *StatsRing) Add(sample StatsSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.count < len(r.samples) {
+		r.count++
+	}
+}
+
+func (r *StatsRing) Len() int {
+	return r.count
+}
+
+/**
+	Copies the last n samples, or all of them if there
+	are fewer, oldest first
+**/
+func (r *StatsRing) Last(n int) []StatsSample {
+	if n > r.count {
+		n = r.count
+	}
+	var result = make([]StatsSample, n)
+	var start = r.next - n
+	if start < 0 {
+		start += len(r.samples)
+	}
+	for i := range result {
+		result[i] = r.samples[(start+i)%len(r.samples)]
+	}
+	return result
+}
+
+func (r *StatsRing) Samples() []StatsSample {
+	return r.Last(r.count)
+}
+
+func CPUValues(samples []StatsSample) []float64 {
+	var values = make([]float64, len(samples))
+	for i := range samples {
+		values[i] = samples[i].CPUPercent
+	}
+	return values
+}
+
+func MemoryValues(samples []StatsSample) []float64 {
+	var values = make([]float64, len(samples))
+	for i := range samples {
+		values[i] = float64(samples[i].MemoryUsage)
+	}
+	return values
+}
+
+/**
+	Picks one of the rates of each sample, like
+	func(rates IORates) float64 { return rates.NetRx }
+**/
+func RateValues(samples []StatsSample, rate func(IORates) float64) []float64 {
+	var values = make([]float64, len(samples))
+	for i := range samples {
+		values[i] = rate(samples[i].IO)
+	}
+	return values
+}
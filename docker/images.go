@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/clidockermgr/ui"
+	"github.com/clidockermgr/util"
 	"github.com/docker/docker/api/types"
 )
 
@@ -17,6 +18,10 @@ func (i ImageItem) Value() interface{} {
 	return &i.image
 }
 
+func (i ImageItem) Key() string {
+	return i.image.ID
+}
+
 func (i ImageItem) String() string {
 
 	var id = i.image.ID[7:19]
@@ -41,13 +46,20 @@ func (i ImageItem) String() string {
 	}
 	durationStr += fmt.Sprintf("%d hs", durationHs)
 
-	return fmt.Sprintf("%s %-60s %-20s %s", id, repo, tag, durationStr)
+	return fmt.Sprintf("%s %-60s %-20s %10s %s", id, repo, tag, util.FormatMemory(uint64(i.image.Size)), durationStr)
 }
 
+const (
+	ImagesListModelSortColumn = 1
+	ImagesListModelSortOrder  = 2
+)
+
 type ImagesListModel struct {
 	ui.BaseListModel
 	dockerClient *ServiceHandler
 	items        []types.ImageSummary
+	sortColumn   int
+	descending   bool
 }
 
 func ImagesListModelNew(dockerClient *ServiceHandler) *ImagesListModel {
@@ -58,8 +70,29 @@ func ImagesListModelNew(dockerClient *ServiceHandler) *ImagesListModel {
 	return &model
 }
 
+func (m *ImagesListModel) SetProperty(property int, value interface{}) {
+	switch property {
+	case ImagesListModelSortColumn:
+		m.sortColumn = (m.sortColumn + 1) % len(ImageSortColumns)
+	case ImagesListModelSortOrder:
+		m.descending = !m.descending
+	default:
+		return
+	}
+	m.Update()
+	m.NotifyChanged()
+}
+
+func (m *ImagesListModel) SortDescription() string {
+	return SortDescription(ImageSortColumns[m.sortColumn].Name, m.descending)
+}
+
+// Sorts a copy, the service keeps its own slice
 func (m *ImagesListModel) Update() {
-	m.items = m.dockerClient.Images()
+	var images = m.dockerClient.Images()
+	m.items = make([]types.ImageSummary, len(images))
+	copy(m.items, images)
+	SortImages(m.items, m.sortColumn, m.descending)
 }
 
 func (m ImagesListModel) ItemCount() int {
@@ -75,3 +108,7 @@ func (m *ImagesListModel) ImagesUpdated() {
 }
 func (m *ImagesListModel) ContainersUpdated() {
 }
+func (m *ImagesListModel) NetworksUpdated() {
+}
+func (m *ImagesListModel) VolumesUpdated() {
+}
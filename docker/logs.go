@@ -0,0 +1,195 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+type LogLine struct {
+	Stderr bool
+	Time   time.Time
+	Text   string
+	// Where the line comes from when merging the logs of several containers
+	Source string
+}
+
+/**
+	Query options for container logs. Since and Until accept
+	relative durations like "10m" or absolute timestamps,
+	an empty Tail means the whole history. Logs are followed
+	unless Until is set or NoFollow asks for the current ones.
+**/
+type LogOptions struct {
+	Tail       string
+	Since      string
+	Until      string
+	Timestamps bool
+	NoFollow   bool
+}
+
+type LogHandler func(line LogLine)
+
+/**
+	Splits whatever is written into lines, passing
+	each complete line to the log handler.
+**/
+type logLineWriter struct {
+	stderr     bool
+	timestamps bool
+	buffer     []byte
+	handler    LogHandler
+}
+
+func (w *logLineWriter) Write(data []byte) (int, error) {
+	w.buffer = append(w.buffer, data...)
+
+	for {
+		var index = bytes.IndexByte(w.buffer, '\n')
+		if index == -1 {
+			break
+		}
+		w.emit(string(bytes.TrimRight(w.buffer[0:index], "\r")))
+		w.buffer = w.buffer[index+1:]
+	}
+	return len(data), nil
+}
+
+func (w *logLineWriter) Flush() {
+	if len(w.buffer) > 0 {
+		w.emit(string(w.buffer))
+		w.buffer = nil
+	}
+}
+
+func (w *logLineWriter) emit(text string) {
+	w.handler(MakeLogLine(w.stderr, text, w.timestamps))
+}
+
+/**
+	Streams the logs of a container line by line to the given handler,
+	keeps following new output until the returned function is called.
+	Output of containers without a TTY is multiplexed, and gets split
+	into stdout and stderr lines.
+**/
+func (s *ServiceHandler) FollowLogs(containerId string, options LogOptions, handler LogHandler) context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go s.ReadLogs(ctx, containerId, options, handler)
+
+	return cancel
+}
+
+/**
+	Streams the logs of a container to the handler and returns once
+	they end, which only happens when following if ctx is cancelled
+**/
+func (s *ServiceHandler) ReadLogs(ctx context.Context, containerId string, options LogOptions, handler LogHandler) {
+	inspect, err := s.client.ContainerInspect(ctx, containerId)
+
+	if err != nil {
+		log.Print("Error inspecting container", err)
+		return
+	}
+
+	var tail = options.Tail
+	if tail == "" {
+		tail = "all"
+	}
+
+	reader, err := s.client.ContainerLogs(ctx, containerId, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     options.Until == "" && !options.NoFollow,
+		Tail:       tail,
+		Since:      options.Since,
+		Until:      options.Until,
+		Timestamps: options.Timestamps,
+	})
+
+	if err != nil {
+		log.Print("Error getting container logs", err)
+		handler(LogLine{Stderr: true, Text: err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	if inspect.Config.Tty {
+		err = ReadLogLines(reader, options.Timestamps, handler)
+	} else {
+		err = DemuxLogLines(reader, options.Timestamps, handler)
+	}
+
+	if err != nil && ctx.Err() == nil {
+		log.Print("Error reading container logs", err)
+	}
+}
+
+/**
+	The current time of the daemon, to ask for logs
+	from it whatever the local clock says
+**/
+func (s *ServiceHandler) DaemonTime(ctx context.Context) (time.Time, error) {
+	info, err := s.client.Info(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, info.SystemTime)
+}
+
+// A time as the since and until log options accept it, with nanoseconds
+func LogTime(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+/**
+	Builds a log line, when timestamps were requested each
+	line starts with the daemon time followed by a space.
+**/
+func MakeLogLine(stderr bool, text string, timestamps bool) LogLine {
+	var line = LogLine{Stderr: stderr, Text: text}
+
+	if timestamps {
+		var index = strings.IndexByte(text, ' ')
+		if index == -1 {
+			index = len(text)
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, text[0:index])
+		if err == nil {
+			line.Time = timestamp.Local()
+			line.Text = strings.TrimPrefix(text[index:], " ")
+		}
+	}
+	return line
+}
+
+func ReadLogLines(reader io.Reader, timestamps bool, handler LogHandler) error {
+	var scanner = bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		handler(MakeLogLine(false, scanner.Text(), timestamps))
+	}
+
+	return scanner.Err()
+}
+
+func DemuxLogLines(reader io.Reader, timestamps bool, handler LogHandler) error {
+	var stdout = logLineWriter{timestamps: timestamps, handler: handler}
+	var stderr = logLineWriter{stderr: true, timestamps: timestamps, handler: handler}
+
+	_, err := stdcopy.StdCopy(&stdout, &stderr, reader)
+
+	stdout.Flush()
+	stderr.Flush()
+
+	return err
+}
@@ -0,0 +1,193 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/clidockermgr/ui"
+	"github.com/docker/docker/api/types"
+)
+
+const (
+	NetworksListModelToggleExpanded = 1
+)
+
+/**
+	Options for creating a network, subnet and
+	gateway are left to docker when empty
+**/
+type NetworkOptions struct {
+	Name     string
+	Driver   string
+	Subnet   string
+	Gateway  string
+	Internal bool
+}
+
+/**
+	A container attached to a network
+**/
+type NetworkEndpoint struct {
+	ContainerID   string
+	ContainerName string
+	IPv4Address   string
+	IPv6Address   string
+}
+
+/**
+	A row of the networks list, either a network or,
+	when the network is expanded, one of its endpoints
+**/
+type NetworkItem struct {
+	network  types.NetworkResource
+	endpoint *NetworkEndpoint
+	expanded bool
+}
+
+func (i NetworkItem) Value() interface{} {
+	return &i.network
+}
+
+func (i NetworkItem) Key() string {
+	if i.endpoint != nil {
+		return i.network.ID + " " + i.endpoint.ContainerID
+	}
+	return i.network.ID
+}
+
+func (i NetworkItem) Endpoint() *NetworkEndpoint {
+	return i.endpoint
+}
+
+func (i NetworkItem) String() string {
+
+	if i.endpoint != nil {
+		return fmt.Sprintf("    %-36s %-20s %s", i.endpoint.ContainerName, i.endpoint.IPv4Address, i.endpoint.IPv6Address)
+	}
+
+	var subnets []string
+	var gateways []string
+
+	for _, config := range i.network.IPAM.Config {
+		if config.Subnet != "" {
+			subnets = append(subnets, config.Subnet)
+		}
+		if config.Gateway != "" {
+			gateways = append(gateways, config.Gateway)
+		}
+	}
+
+	var marker = "+"
+	if i.expanded {
+		marker = "-"
+	}
+
+	var name = i.network.Name
+
+	if len(name) > 30 {
+		name = name[0:27] + "..."
+	}
+
+	return fmt.Sprintf("%s %-12s %-30s %-10s %-6s %-30s %s", marker, i.network.ID[0:12], name, i.network.Driver, i.network.Scope,
+		strings.Join(subnets, ", "), strings.Join(gateways, ", "))
+}
+
+type NetworksListModel struct {
+	ui.BaseListModel
+	dockerClient *ServiceHandler
+	expanded     map[string]bool
+	items        []NetworkItem
+}
+
+func NetworksListModelNew(dockerClient *ServiceHandler) *NetworksListModel {
+	var model = NetworksListModel{dockerClient: dockerClient, expanded: make(map[string]bool)}
+	model.Init()
+	model.Update()
+	dockerClient.AddListener(&model)
+	return &model
+}
+
+func (m *NetworksListModel) SetProperty(property int, value interface{}) {
+	switch property {
+	case NetworksListModelToggleExpanded:
+		var networkId = value.(string)
+		m.expanded[networkId] = !m.expanded[networkId]
+		m.Update()
+		m.NotifyChanged()
+	}
+}
+
+func FormatAddress(address string, prefixLen int) string {
+	if address == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", address, prefixLen)
+}
+
+/**
+	Builds the rows, placing the endpoints of each
+	expanded network right after it
+**/
+func (m *NetworksListModel) Update() {
+	var networks = m.dockerClient.Networks()
+	var containers = m.dockerClient.Containers()
+	var endpoints = make(map[string][]NetworkEndpoint)
+
+	for i := range containers {
+		var container = containers[i].container
+		if container.NetworkSettings == nil {
+			continue
+		}
+		var name = container.ID[0:12]
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		for _, settings := range container.NetworkSettings.Networks {
+			endpoints[settings.NetworkID] = append(endpoints[settings.NetworkID], NetworkEndpoint{
+				ContainerID:   container.ID,
+				ContainerName: name,
+				IPv4Address:   FormatAddress(settings.IPAddress, settings.IPPrefixLen),
+				IPv6Address:   FormatAddress(settings.GlobalIPv6Address, settings.GlobalIPv6PrefixLen),
+			})
+		}
+	}
+
+	var items []NetworkItem
+
+	for i := range networks {
+		var expanded = m.expanded[networks[i].ID]
+		items = append(items, NetworkItem{network: networks[i], expanded: expanded})
+
+		if expanded {
+			var networkEndpoints = endpoints[networks[i].ID]
+			sort.Slice(networkEndpoints, func(a, b int) bool {
+				return networkEndpoints[a].ContainerName < networkEndpoints[b].ContainerName
+			})
+			for e := range networkEndpoints {
+				items = append(items, NetworkItem{network: networks[i], endpoint: &networkEndpoints[e]})
+			}
+		}
+	}
+	m.items = items
+}
+
+func (m NetworksListModel) ItemCount() int {
+	return len(m.items)
+}
+
+func (m NetworksListModel) Item(index int) ui.ListItem {
+	return &m.items[index]
+}
+func (m *NetworksListModel) ImagesUpdated() {
+}
+func (m *NetworksListModel) ContainersUpdated() {
+	m.Update()
+	m.NotifyChanged()
+}
+func (m *NetworksListModel) VolumesUpdated() {
+}
+func (m *NetworksListModel) NetworksUpdated() {
+	m.Update()
+	m.NotifyChanged()
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/docker/docker/api/types"
+)
+
+type ProgressDetail struct {
+	Current int64 `json:"current"`
+	Total   int64 `json:"total"`
+}
+
+/**
+	A message of the JSON streams returned by
+	operations like pulling or building images
+**/
+type ProgressMessage struct {
+	ID       string         `json:"id"`
+	Status   string         `json:"status"`
+	Stream   string         `json:"stream"`
+	Progress ProgressDetail `json:"progressDetail"`
+	Error    string         `json:"error"`
+}
+
+type ProgressHandler func(message ProgressMessage)
+
+/**
+	Decodes a stream of progress messages passing each one to the
+	handler, the first error reported in the stream is returned.
+**/
+func ReadProgress(reader io.Reader, handler ProgressHandler) error {
+	var decoder = json.NewDecoder(reader)
+	var streamError error
+
+	for {
+		var message ProgressMessage
+
+		err := decoder.Decode(&message)
+
+		if err == io.EOF {
+			return streamError
+		}
+		if err != nil {
+			return err
+		}
+		if message.Error != "" && streamError == nil {
+			streamError = errors.New(message.Error)
+		}
+		handler(message)
+	}
+}
+
+/**
+	Pulls an image, blocks until the pull is finished
+**/
+func (s *ServiceHandler) PullImage(reference string, handler ProgressHandler) error {
+	reader, err := s.client.ImagePull(context.Background(), reference, types.ImagePullOptions{})
+
+	if err == nil {
+		defer reader.Close()
+		err = ReadProgress(reader, handler)
+	}
+
+	if err != nil {
+		log.Print("Error pulling image", err)
+	}
+	s.RefreshImages()
+	return err
+}
@@ -4,56 +4,106 @@ import (
 	"container/list"
 	"context"
 	"encoding/json"
-	"io/ioutil"
 	"log"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/clidockermgr/util"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 )
 
+const (
+	ResyncInterval = 30 * time.Second
+	StatsInterval  = time.Second
+	// Samples kept in the history of each container, about one per second
+	StatsHistoryLength = 120
+
+	DefaultStopTimeout = 10 * time.Second
+	PingTimeout        = 10 * time.Second
+)
+
 type ServiceListener interface {
 	ImagesUpdated()
 	ContainersUpdated()
+	VolumesUpdated()
+	NetworksUpdated()
 }
 
 type ContainerSummary struct {
 	container types.Container
-	stats     types.Stats
+	stats     types.StatsJSON
+	ioRates   IORates
+	recent    []StatsSample
 	diskUsage int64
 }
 
-type StatData struct {
-	id    string
-	stats types.Stats
-}
-
 type ServiceHandler struct {
 	client           *client.Client
 	active           bool
 	activeContainers bool
+	mutex            sync.Mutex
 	images           []types.ImageSummary
 	containers       []ContainerSummary
+	volumes          []types.Volume
+	networks         []types.NetworkResource
 	listeners        *list.List
 	diskUsage        map[string]int64
+	history          map[string]*StatsRing
+	statsReaders     map[string]*statsReader
+	latestStats      map[string]types.StatsJSON
+	ioRates          map[string]IORates
+	statsChanged     bool
+	stopTimeout      time.Duration
+	cancelEvents     context.CancelFunc
 }
 
 func ServiceHandlerNew(client *client.Client) *ServiceHandler {
 	handler := ServiceHandler{
-		client:    client,
-		active:    true,
-		listeners: list.New(),
-		diskUsage: make(map[string]int64),
+		client:       client,
+		active:       true,
+		listeners:    list.New(),
+		diskUsage:    make(map[string]int64),
+		history:      make(map[string]*StatsRing),
+		statsReaders: make(map[string]*statsReader),
+		latestStats:  make(map[string]types.StatsJSON),
+		ioRates:      make(map[string]IORates),
+		stopTimeout:  DefaultStopTimeout,
 	}
 
-	go handler.UpdateContainers()
-	go handler.UpdateImages()
-	go handler.GetSystemStats()
+	go handler.WatchEvents()
+	go handler.NotifyStats()
+	go handler.PeriodicResync()
 	return &handler
 }
 
+/**
+	Stops watching the daemon and closes the client,
+	the handler can not be used afterwards
+**/
+func (s *ServiceHandler) Close() {
+	s.mutex.Lock()
+	s.active = false
+	var cancel = s.cancelEvents
+	s.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.StopStatsReaders()
+	s.client.Close()
+}
+
+func (s *ServiceHandler) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), PingTimeout)
+	defer cancel()
+	_, err := s.client.Ping(ctx)
+	return err
+}
+
 func (s *ServiceHandler) AddListener(listener interface{}) {
 	s.listeners.PushBack(listener)
 }
@@ -70,123 +120,425 @@ func (s *ServiceHandler) NotifyImagesUpdated() {
 	}
 }
 
+func (s *ServiceHandler) NotifyVolumesUpdated() {
+	for v := s.listeners.Front(); v != nil; v = v.Next() {
+		v.Value.(ServiceListener).VolumesUpdated()
+	}
+}
+
+func (s *ServiceHandler) NotifyNetworksUpdated() {
+	for v := s.listeners.Front(); v != nil; v = v.Next() {
+		v.Value.(ServiceListener).NetworksUpdated()
+	}
+}
+
 func (s *ServiceHandler) Containers() []ContainerSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result = make([]ContainerSummary, len(s.containers))
+
 	for i := range s.containers {
+		result[i] = s.containers[i]
 		val, ok := s.diskUsage[s.containers[i].container.ID]
 		if ok {
-			s.containers[i].diskUsage = val
+			result[i].diskUsage = val
+		}
+		result[i].stats = s.latestStats[s.containers[i].container.ID]
+		result[i].ioRates = s.ioRates[s.containers[i].container.ID]
+		if history, ok := s.history[s.containers[i].container.ID]; ok {
+			result[i].recent = history.Last(RecentSamples)
 		}
 	}
-	return s.containers
+	return result
 }
 
 func (s *ServiceHandler) Images() []types.ImageSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	return s.images
 }
 
-func (s *ServiceHandler) UpdateContainers() {
+func (s *ServiceHandler) Volumes() []types.Volume {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.volumes
+}
+
+func (s *ServiceHandler) Networks() []types.NetworkResource {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.networks
+}
+
+/**
+	Reloads containers, images, networks and disk usage from scratch.
+	Used when the events stream is (re)connected and as a periodic
+	safety net for anything the events did not tell us about.
+**/
+func (s *ServiceHandler) Resync() {
+	s.RefreshContainers()
+	s.RefreshImages()
+	s.RefreshNetworks()
+	s.RefreshDiskUsage()
+}
+
+func (s *ServiceHandler) PeriodicResync() {
 	for s.active {
-		containers, err := s.client.ContainerList(context.Background(), types.ContainerListOptions{All: !s.activeContainers})
+		time.Sleep(ResyncInterval)
+		s.Resync()
+	}
+}
+
+func (s *ServiceHandler) RefreshContainers() {
+	containers, err := s.client.ContainerList(context.Background(), types.ContainerListOptions{All: !s.activeContainers})
 
-		if err != nil {
-			log.Print(err)
-		} else {
-			s.DoUpdateContainers(containers)
+	if err != nil {
+		log.Printf("Error getting containers: %s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	var summaries = make([]ContainerSummary, len(containers))
+	var changed = len(containers) != len(s.containers)
+
+	for i := range containers {
+		summaries[i] = ContainerSummary{container: containers[i]}
+		if !changed && !reflect.DeepEqual(s.containers[i].container, containers[i]) {
+			changed = true
 		}
+	}
+	s.containers = summaries
+
+	for id := range s.history {
+		if s.containerIndex(id) == -1 {
+			delete(s.history, id)
+		}
+	}
+	s.mutex.Unlock()
+
+	s.SyncStatsReaders()
+
+	if changed {
 		s.NotifyContainersUpdated()
-		time.Sleep(time.Second)
 	}
 }
 
-func (s *ServiceHandler) UpdateImages() {
-	for s.active {
-		images, err := s.client.ImageList(context.Background(), types.ImageListOptions{})
+/**
+	Reloads a single container, adding it if it is new and
+	dropping it if it no longer exists.
+**/
+func (s *ServiceHandler) RefreshContainer(containerId string) {
+	containers, err := s.client.ContainerList(context.Background(), types.ContainerListOptions{
+		All:     !s.activeContainers,
+		Filters: filters.NewArgs(filters.Arg("id", containerId)),
+	})
+
+	if err != nil {
+		log.Printf("Error getting container %s: %s", containerId, err)
+		return
+	}
+
+	if len(containers) == 0 {
+		s.DropContainer(containerId)
+		return
+	}
+
+	s.mutex.Lock()
+	var changed = true
+	var index = s.containerIndex(containerId)
+
+	if index == -1 {
+		s.containers = append([]ContainerSummary{{container: containers[0]}}, s.containers...)
+	} else if reflect.DeepEqual(s.containers[index].container, containers[0]) {
+		changed = false
+	} else {
+		s.containers[index].container = containers[0]
+	}
+	s.mutex.Unlock()
+
+	s.SyncStatsReaders()
+
+	if changed {
+		s.NotifyContainersUpdated()
+	}
+}
+
+func (s *ServiceHandler) DropContainer(containerId string) {
+	s.mutex.Lock()
+	var index = s.containerIndex(containerId)
+	if index != -1 {
+		s.containers = append(s.containers[0:index:index], s.containers[index+1:]...)
+	}
+	delete(s.diskUsage, containerId)
+	delete(s.history, containerId)
+	delete(s.latestStats, containerId)
+	delete(s.ioRates, containerId)
+	s.mutex.Unlock()
+
+	s.SyncStatsReaders()
 
-		if err != nil {
-			log.Printf("Error getting images: %s", err)
-		} else {
-			s.images = images
+	if index != -1 {
+		s.NotifyContainersUpdated()
+	}
+}
+
+func (s *ServiceHandler) containerIndex(containerId string) int {
+	for i := range s.containers {
+		if s.containers[i].container.ID == containerId {
+			return i
 		}
+	}
+	return -1
+}
+
+func (s *ServiceHandler) RefreshImages() {
+	images, err := s.client.ImageList(context.Background(), types.ImageListOptions{})
+
+	if err != nil {
+		log.Printf("Error getting images: %s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	var changed = !reflect.DeepEqual(s.images, images)
+	s.images = images
+	s.mutex.Unlock()
+
+	if changed {
 		s.NotifyImagesUpdated()
-		time.Sleep(time.Second)
 	}
 }
 
-func (s *ServiceHandler) GetSystemStats() {
-	for s.active {
-		diskUsage, err := s.client.DiskUsage(context.Background())
-
-		if err != nil {
-			log.Printf("Error getting disk usage: %s", err)
-		} else {
-			for i := range diskUsage.Containers {
-				s.diskUsage[diskUsage.Containers[i].ID] = diskUsage.Containers[i].SizeRw
-			}
+func (s *ServiceHandler) DropImage(imageId string) {
+	s.mutex.Lock()
+	var images []types.ImageSummary
+	for i := range s.images {
+		if s.images[i].ID != imageId {
+			images = append(images, s.images[i])
 		}
-		time.Sleep(time.Second)
+	}
+	var changed = len(images) != len(s.images)
+	s.images = images
+	s.mutex.Unlock()
+
+	if changed {
+		s.NotifyImagesUpdated()
 	}
 }
 
-func (s *ServiceHandler) DoUpdateContainers(containers []types.Container) {
+func (s *ServiceHandler) RefreshNetworks() {
+	networks, err := s.client.NetworkList(context.Background(), types.NetworkListOptions{})
 
-	var summaries []ContainerSummary
-	var wg sync.WaitGroup
+	if err != nil {
+		log.Printf("Error getting networks: %s", err)
+		return
+	}
 
-	wg.Add(len(containers))
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Name < networks[j].Name
+	})
 
-	for i := range containers {
-		summaries = append(summaries, ContainerSummary{container: containers[i]})
+	s.mutex.Lock()
+	var changed = !reflect.DeepEqual(s.networks, networks)
+	s.networks = networks
+	s.mutex.Unlock()
 
-		go func(i int) {
-			defer wg.Done()
-			stats, err := s.client.ContainerStats(context.Background(), containers[i].ID, false)
-			if err == nil {
-				summaries[i].stats = *util.ParseStatsBody(stats.Body)
-			} else {
-				log.Printf("Error getting stats for container %s %s", containers[i].ID, err)
-			}
-		}(i)
+	if changed {
+		s.NotifyNetworksUpdated()
+	}
+}
+
+func (s *ServiceHandler) RefreshDiskUsage() {
+	diskUsage, err := s.client.DiskUsage(context.Background())
 
+	if err != nil {
+		log.Printf("Error getting disk usage: %s", err)
+		return
+	}
+
+	var volumes = make([]types.Volume, len(diskUsage.Volumes))
+	for i := range diskUsage.Volumes {
+		volumes[i] = *diskUsage.Volumes[i]
+	}
+
+	s.mutex.Lock()
+	var changed = false
+	for i := range diskUsage.Containers {
+		var id = diskUsage.Containers[i].ID
+		if s.diskUsage[id] != diskUsage.Containers[i].SizeRw {
+			s.diskUsage[id] = diskUsage.Containers[i].SizeRw
+			changed = true
+		}
+	}
+	var volumesChanged = !reflect.DeepEqual(s.volumes, volumes)
+	s.volumes = volumes
+	s.mutex.Unlock()
+
+	if changed {
+		s.NotifyContainersUpdated()
+	}
+	if volumesChanged {
+		s.NotifyVolumesUpdated()
 	}
-	wg.Wait()
-	s.containers = summaries
 }
 
-func (s *ServiceHandler) RemoveImage(imageId string) {
-	_, err := s.client.ImageRemove(context.Background(), imageId, types.ImageRemoveOptions{})
+func (s *ServiceHandler) RemoveImage(imageId string, force bool) error {
+	_, err := s.client.ImageRemove(context.Background(), imageId, types.ImageRemoveOptions{Force: force})
 
 	if err != nil {
 		log.Print("Error removing image", err)
 	}
+	return err
 }
 
-func (s *ServiceHandler) RemoveContainer(containerId string) {
-	err := s.client.ContainerRemove(context.Background(), containerId, types.ContainerRemoveOptions{})
+// Forcing removes running containers, killing them first
+func (s *ServiceHandler) RemoveContainer(containerId string, force bool, removeVolumes bool) error {
+	err := s.client.ContainerRemove(context.Background(), containerId, types.ContainerRemoveOptions{Force: force, RemoveVolumes: removeVolumes})
 
 	if err != nil {
 		log.Print("Error removing container", err)
 	}
+	return err
+}
+
+// Time containers are given to stop before being killed
+func (s *ServiceHandler) SetStopTimeout(timeout time.Duration) {
+	s.stopTimeout = timeout
+}
+
+func (s *ServiceHandler) StartContainer(containerId string) error {
+	err := s.client.ContainerStart(context.Background(), containerId, types.ContainerStartOptions{})
+
+	if err != nil {
+		log.Print("Error starting container", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) StopContainer(containerId string) error {
+	var timeout = s.stopTimeout
+	err := s.client.ContainerStop(context.Background(), containerId, &timeout)
+
+	if err != nil {
+		log.Print("Error stopping container", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) RestartContainer(containerId string) error {
+	var timeout = s.stopTimeout
+	err := s.client.ContainerRestart(context.Background(), containerId, &timeout)
+
+	if err != nil {
+		log.Print("Error restarting container", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) PauseContainer(containerId string) error {
+	err := s.client.ContainerPause(context.Background(), containerId)
+
+	if err != nil {
+		log.Print("Error pausing container", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) UnpauseContainer(containerId string) error {
+	err := s.client.ContainerUnpause(context.Background(), containerId)
+
+	if err != nil {
+		log.Print("Error unpausing container", err)
+	}
+	return err
 }
 
-func (s *ServiceHandler) KillContainer(containerId string) {
-	err := s.client.ContainerKill(context.Background(), containerId, "9")
+// The signal can be given either by name, like SIGHUP, or by number
+func (s *ServiceHandler) KillContainer(containerId string, signal string) error {
+	err := s.client.ContainerKill(context.Background(), containerId, signal)
 
 	if err != nil {
 		log.Print("Error killing container", err)
 	}
+	return err
+}
+
+func (s *ServiceHandler) RemoveVolume(volumeName string, force bool) error {
+	err := s.client.VolumeRemove(context.Background(), volumeName, force)
+
+	if err != nil {
+		log.Print("Error removing volume", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) InspectVolume(volumeName string) string {
+	inspect, err := s.client.VolumeInspect(context.Background(), volumeName)
+	if err == nil {
+		result, err := json.MarshalIndent(inspect, "", "    ")
+
+		if err == nil {
+			return string(result)
+		}
+	} else {
+		log.Print("Error inspecting volume", err)
+	}
+	return ""
+}
+
+func (s *ServiceHandler) CreateNetwork(options NetworkOptions) error {
+	var create = types.NetworkCreate{CheckDuplicate: true, Driver: options.Driver, Internal: options.Internal}
+
+	if options.Subnet != "" {
+		create.IPAM = &network.IPAM{Config: []network.IPAMConfig{{Subnet: options.Subnet, Gateway: options.Gateway}}}
+	}
+
+	_, err := s.client.NetworkCreate(context.Background(), options.Name, create)
+
+	if err != nil {
+		log.Print("Error creating network", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) RemoveNetwork(networkId string) error {
+	err := s.client.NetworkRemove(context.Background(), networkId)
+
+	if err != nil {
+		log.Print("Error removing network", err)
+	}
+	return err
 }
 
-func (s *ServiceHandler) Logs(containerId string) string {
-	reader, err := s.client.ContainerLogs(context.Background(), containerId, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+func (s *ServiceHandler) ConnectNetwork(networkId string, containerId string) error {
+	err := s.client.NetworkConnect(context.Background(), networkId, containerId, nil)
 
+	if err != nil {
+		log.Print("Error connecting container to network", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) DisconnectNetwork(networkId string, containerId string) error {
+	err := s.client.NetworkDisconnect(context.Background(), networkId, containerId, false)
+
+	if err != nil {
+		log.Print("Error disconnecting container from network", err)
+	}
+	return err
+}
+
+func (s *ServiceHandler) InspectNetwork(networkId string) string {
+	inspect, err := s.client.NetworkInspect(context.Background(), networkId, types.NetworkInspectOptions{Verbose: true})
 	if err == nil {
-		result, err := ioutil.ReadAll(reader)
+		result, err := json.MarshalIndent(inspect, "", "    ")
 
 		if err == nil {
 			return string(result)
 		}
 	} else {
-		log.Print("Error getting container logs", err)
+		log.Print("Error inspecting network", err)
 	}
 	return ""
 }
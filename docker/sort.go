@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/clidockermgr/util"
+	"github.com/docker/docker/api/types"
+)
+
+// Orders two rows, negative when a goes first
+type containerCompare func(a, b *ContainerSummary) int
+type imageCompare func(a, b *types.ImageSummary) int
+
+type ContainerSortColumn struct {
+	Name    string
+	compare containerCompare
+}
+
+type ImageSortColumn struct {
+	Name    string
+	compare imageCompare
+}
+
+func compareStrings(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func containerCPU(summary *ContainerSummary) float64 {
+	if summary.container.State != "running" {
+		return 0
+	}
+	return util.CPUPercent(&summary.stats.Stats)
+}
+
+func containerSortName(summary *ContainerSummary) string {
+	if len(summary.container.Names) > 0 {
+		return strings.TrimPrefix(summary.container.Names[0], "/")
+	}
+	return summary.container.ID
+}
+
+/**
+	Columns the containers list can be sorted by, in the
+	order they are cycled through, the first one by default
+**/
+var ContainerSortColumns = []ContainerSortColumn{
+	{"name", func(a, b *ContainerSummary) int {
+		return compareStrings(containerSortName(a), containerSortName(b))
+	}},
+	{"image", func(a, b *ContainerSummary) int {
+		return compareStrings(a.container.Image, b.container.Image)
+	}},
+	{"status", func(a, b *ContainerSummary) int {
+		if result := compareStrings(a.container.State, b.container.State); result != 0 {
+			return result
+		}
+		return compareStrings(a.container.Status, b.container.Status)
+	}},
+	{"created", func(a, b *ContainerSummary) int {
+		return compareInts(a.container.Created, b.container.Created)
+	}},
+	{"cpu", func(a, b *ContainerSummary) int {
+		return compareFloats(containerCPU(a), containerCPU(b))
+	}},
+	{"memory", func(a, b *ContainerSummary) int {
+		return compareInts(int64(a.stats.MemoryStats.Usage), int64(b.stats.MemoryStats.Usage))
+	}},
+	{"disk usage", func(a, b *ContainerSummary) int {
+		return compareInts(a.diskUsage, b.diskUsage)
+	}},
+	{"network i/o", func(a, b *ContainerSummary) int {
+		return compareFloats(a.ioRates.NetRx+a.ioRates.NetTx, b.ioRates.NetRx+b.ioRates.NetTx)
+	}},
+	{"block i/o", func(a, b *ContainerSummary) int {
+		return compareFloats(a.ioRates.BlockRead+a.ioRates.BlockWrite, b.ioRates.BlockRead+b.ioRates.BlockWrite)
+	}},
+}
+
+var ImageSortColumns = []ImageSortColumn{
+	{"age", func(a, b *types.ImageSummary) int {
+		return compareInts(b.Created, a.Created)
+	}},
+	{"size", func(a, b *types.ImageSummary) int {
+		return compareInts(a.Size, b.Size)
+	}},
+	{"name", func(a, b *types.ImageSummary) int {
+		return compareStrings(ImageSortName(a), ImageSortName(b))
+	}},
+}
+
+func ImageSortName(image *types.ImageSummary) string {
+	if len(image.RepoTags) > 0 {
+		return image.RepoTags[len(image.RepoTags)-1]
+	}
+	return image.ID
+}
+
+/**
+	Sorts the containers by a column, ties are sorted by ID
+	so that rows do not move around between refreshes
+**/
+func SortContainers(items []ContainerSummary, column int, descending bool) {
+	var compare = ContainerSortColumns[column].compare
+	sort.Slice(items, func(i, j int) bool {
+		var result = compare(&items[i], &items[j])
+		if descending {
+			result = -result
+		}
+		if result == 0 {
+			return items[i].container.ID < items[j].container.ID
+		}
+		return result < 0
+	})
+}
+
+func SortImages(items []types.ImageSummary, column int, descending bool) {
+	var compare = ImageSortColumns[column].compare
+	sort.Slice(items, func(i, j int) bool {
+		var result = compare(&items[i], &items[j])
+		if descending {
+			result = -result
+		}
+		if result == 0 {
+			return items[i].ID < items[j].ID
+		}
+		return result < 0
+	})
+}
+
+/**
+	Describes the sort order, for the titles of the views
+**/
+func SortDescription(column string, descending bool) string {
+	if descending {
+		return "by " + column + ", descending"
+	}
+	return "by " + column
+}
@@ -0,0 +1,133 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The host given to clients going through ssh, requests are sent over the ssh connection
+const SSHClientHost = "http://docker.example.com"
+
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+/**
+	Connects to the docker daemon in an ssh://[user@]host[:port] endpoint,
+	running "docker system dial-stdio" there, as the docker CLI does.
+**/
+func SSHDialer(endpoint *url.URL) DialContextFunc {
+	var args []string
+	if endpoint.Port() != "" {
+		args = append(args, "-p", endpoint.Port())
+	}
+	var host = endpoint.Hostname()
+	if endpoint.User != nil {
+		host = endpoint.User.Username() + "@" + host
+	}
+	args = append(args, "--", host, "docker", "system", "dial-stdio")
+
+	// A hanging ssh, like on an unreachable host, is killed when the
+	// request using the connection gives up and closes it
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return CommandConnNew(ctx, "ssh", args...)
+	}
+}
+
+/**
+	Collects the error output of a command, which exec
+	writes from its own goroutine while the command runs
+**/
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
+
+/**
+	A connection over the standard input and output of a command,
+	which lives until the connection is closed. The dial context can
+	be the one of the request the connection was dialed for, and the
+	connection stays in the pool after it, so ctx only stops the
+	command when it ends before the command started.
+**/
+type CommandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr lockedBuffer
+}
+
+func CommandConnNew(ctx context.Context, command string, args ...string) (*CommandConn, error) {
+	var conn = CommandConn{cmd: exec.Command(command, args...)}
+	var err error
+
+	conn.cmd.Stderr = &conn.stderr
+	if conn.stdin, err = conn.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if conn.stdout, err = conn.cmd.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	if err = conn.cmd.Start(); err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &conn, nil
+}
+
+func (c *CommandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if err == io.EOF {
+		if message := strings.TrimSpace(c.stderr.String()); message != "" {
+			return n, fmt.Errorf("%s: %s", c.cmd.Path, message)
+		}
+	}
+	return n, err
+}
+
+func (c *CommandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *CommandConn) Close() error {
+	c.stdin.Close()
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	c.cmd.Wait()
+	return nil
+}
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string { return "command" }
+func (commandAddr) String() string  { return "command" }
+
+func (c *CommandConn) LocalAddr() net.Addr  { return commandAddr{} }
+func (c *CommandConn) RemoteAddr() net.Addr { return commandAddr{} }
+
+// Deadlines are not supported, requests are limited by their contexts
+func (c *CommandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *CommandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *CommandConn) SetWriteDeadline(t time.Time) error { return nil }
@@ -0,0 +1,261 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/clidockermgr/util"
+	"github.com/docker/docker/api/types"
+)
+
+/**
+	Follows the stats stream of a running container
+	until it stops or the reader gets cancelled
+**/
+type statsReader struct {
+	containerId string
+	cancel      context.CancelFunc
+}
+
+/**
+	Starts a stats reader for every running container which has
+	none, and stops the readers of containers no longer running.
+**/
+func (s *ServiceHandler) SyncStatsReaders() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.active {
+		return
+	}
+
+	var running = make(map[string]bool)
+	for i := range s.containers {
+		if s.containers[i].container.State == "running" {
+			running[s.containers[i].container.ID] = true
+		}
+	}
+
+	for id, reader := range s.statsReaders {
+		if !running[id] {
+			reader.cancel()
+			delete(s.statsReaders, id)
+			delete(s.latestStats, id)
+			delete(s.ioRates, id)
+			s.statsChanged = true
+		}
+	}
+
+	for id := range running {
+		if s.statsReaders[id] == nil {
+			ctx, cancel := context.WithCancel(context.Background())
+			var reader = statsReader{containerId: id, cancel: cancel}
+			s.statsReaders[id] = &reader
+			go s.ReadStats(ctx, &reader)
+		}
+	}
+}
+
+func (s *ServiceHandler) StopStatsReaders() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, reader := range s.statsReaders {
+		reader.cancel()
+		delete(s.statsReaders, id)
+	}
+}
+
+/**
+	Reads the samples the daemon streams, about one per second,
+	into the latest stats cache and the CPU history.
+**/
+func (s *ServiceHandler) ReadStats(ctx context.Context, reader *statsReader) {
+	// The daemon ends the stream when the container stops, usually before its event arrives
+	defer func() {
+		s.mutex.Lock()
+		if s.statsReaders[reader.containerId] == reader {
+			delete(s.statsReaders, reader.containerId)
+			delete(s.latestStats, reader.containerId)
+			delete(s.ioRates, reader.containerId)
+			s.statsChanged = true
+		}
+		s.mutex.Unlock()
+	}()
+
+	response, err := s.client.ContainerStats(ctx, reader.containerId, true)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Error getting stats for container %s %s", reader.containerId, err)
+		}
+		return
+	}
+	defer response.Body.Close()
+
+	var decoder = json.NewDecoder(response.Body)
+	for {
+		var stats types.StatsJSON
+		if err = decoder.Decode(&stats); err != nil {
+			if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+				log.Printf("Error reading stats for container %s %s", reader.containerId, err)
+			}
+			return
+		}
+		s.addSample(reader, stats)
+	}
+}
+
+func (s *ServiceHandler) addSample(reader *statsReader, stats types.StatsJSON) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.statsReaders[reader.containerId] != reader {
+		return
+	}
+
+	var previous = s.latestStats[reader.containerId]
+	var rates = ComputeIORates(&previous, &stats)
+
+	if StatsChanged(previous, stats) || rates != s.ioRates[reader.containerId] {
+		s.statsChanged = true
+	}
+
+	// The first sample has no previous one to compute the CPU usage from
+	if !stats.PreRead.IsZero() {
+		s.addHistory(reader.containerId, StatsSample{
+			Time:        stats.Read,
+			CPUPercent:  util.CPUPercent(&stats.Stats),
+			MemoryUsage: stats.MemoryStats.Usage,
+			MemoryLimit: stats.MemoryStats.Limit,
+			IO:          rates,
+		})
+		// The history shows in the list, which then changes with each sample
+		s.statsChanged = true
+	}
+	s.latestStats[reader.containerId] = stats
+	s.ioRates[reader.containerId] = rates
+}
+
+/**
+	Network and block device throughput, in bytes per second
+**/
+type IORates struct {
+	NetRx      float64
+	NetTx      float64
+	BlockRead  float64
+	BlockWrite float64
+}
+
+type ioTotals struct {
+	netRx, netTx, blockRead, blockWrite uint64
+}
+
+func sumIO(stats *types.StatsJSON) ioTotals {
+	var totals ioTotals
+	for _, network := range stats.Networks {
+		totals.netRx += network.RxBytes
+		totals.netTx += network.TxBytes
+	}
+	// cgroup v1 reports Read and Write, cgroup v2 read and write
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			totals.blockRead += entry.Value
+		case "write":
+			totals.blockWrite += entry.Value
+		}
+	}
+	return totals
+}
+
+// Counters going back, like after a restart, give no rate
+func rate(previous, current uint64, seconds float64) float64 {
+	if current < previous {
+		return 0
+	}
+	return float64(current-previous) / seconds
+}
+
+/**
+	Rates from the counters of two consecutive samples
+**/
+func ComputeIORates(previous, current *types.StatsJSON) IORates {
+	if previous.Read.IsZero() {
+		return IORates{}
+	}
+	var seconds = current.Read.Sub(previous.Read).Seconds()
+	if seconds <= 0 {
+		return IORates{}
+	}
+
+	var before = sumIO(previous)
+	var after = sumIO(current)
+	return IORates{
+		NetRx:      rate(before.netRx, after.netRx, seconds),
+		NetTx:      rate(before.netTx, after.netTx, seconds),
+		BlockRead:  rate(before.blockRead, after.blockRead, seconds),
+		BlockWrite: rate(before.blockWrite, after.blockWrite, seconds),
+	}
+}
+
+/**
+	Stats are not reported through events, they are streamed by a
+	reader per running container. Listeners are notified at most once
+	per interval, when some sample actually differs from the previous one.
+**/
+func (s *ServiceHandler) NotifyStats() {
+	for s.active {
+		time.Sleep(StatsInterval)
+
+		s.mutex.Lock()
+		var changed = s.statsChanged
+		s.statsChanged = false
+		s.mutex.Unlock()
+
+		if changed {
+			s.NotifyContainersUpdated()
+		}
+	}
+}
+
+func (s *ServiceHandler) addHistory(containerId string, sample StatsSample) {
+	var history = s.history[containerId]
+	if history == nil {
+		history = StatsRingNew(StatsHistoryLength)
+		s.history[containerId] = history
+	}
+	history.Add(sample)
+}
+
+/**
+	The samples kept for a container, oldest first
+**/
+func (s *ServiceHandler) History(containerId string) []StatsSample {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if history, ok := s.history[containerId]; ok {
+		return history.Samples()
+	}
+	return nil
+}
+
+/**
+	The CPU percentages of the last samples of a container, oldest first
+**/
+func (s *ServiceHandler) CPUHistory(containerId string) []float64 {
+	return CPUValues(s.History(containerId))
+}
+
+// Sample timestamps always differ, only the measured values are compared
+func StatsChanged(previous, current types.StatsJSON) bool {
+	current.Read = previous.Read
+	current.PreRead = previous.PreRead
+	return !reflect.DeepEqual(previous, current)
+}
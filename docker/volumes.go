@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/clidockermgr/ui"
+	"github.com/clidockermgr/util"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+)
+
+type VolumeItem struct {
+	volume     types.Volume
+	containers []string
+}
+
+func (i VolumeItem) Value() interface{} {
+	return &i.volume
+}
+
+func (i VolumeItem) Key() string {
+	return i.volume.Name
+}
+
+func (i VolumeItem) String() string {
+
+	var name = i.volume.Name
+
+	// anonymous volumes are named after a long hash
+	if len(name) == 64 {
+		name = name[0:12]
+	}
+
+	if len(name) > 30 {
+		name = "..." + name[len(name)-27:]
+	}
+
+	var mountpoint = i.volume.Mountpoint
+
+	if len(mountpoint) > 50 {
+		mountpoint = "..." + mountpoint[len(mountpoint)-47:]
+	}
+
+	var size = "-"
+	var refCount = "-"
+
+	if i.volume.UsageData != nil {
+		if i.volume.UsageData.Size >= 0 {
+			size = util.FormatMemory(uint64(i.volume.UsageData.Size))
+		}
+		if i.volume.UsageData.RefCount >= 0 {
+			refCount = fmt.Sprintf("%d", i.volume.UsageData.RefCount)
+		}
+	}
+
+	return fmt.Sprintf("%-30s %-10s %-50s %10s %4s %s", name, i.volume.Driver, mountpoint, size, refCount, strings.Join(i.containers, ", "))
+}
+
+type VolumesListModel struct {
+	ui.BaseListModel
+	dockerClient *ServiceHandler
+	items        []VolumeItem
+}
+
+func VolumesListModelNew(dockerClient *ServiceHandler) *VolumesListModel {
+	var model = VolumesListModel{dockerClient: dockerClient}
+	model.Init()
+	model.Update()
+	dockerClient.AddListener(&model)
+	return &model
+}
+
+/**
+	Builds the items, matching volumes with the
+	containers which mount them
+**/
+func (m *VolumesListModel) Update() {
+	var volumes = m.dockerClient.Volumes()
+	var containers = m.dockerClient.Containers()
+	var mountedBy = make(map[string][]string)
+
+	for i := range containers {
+		var container = containers[i].container
+		var name = container.ID[0:12]
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		for _, mountPoint := range container.Mounts {
+			if mountPoint.Type == mount.TypeVolume {
+				mountedBy[mountPoint.Name] = append(mountedBy[mountPoint.Name], name)
+			}
+		}
+	}
+
+	var items = make([]VolumeItem, len(volumes))
+
+	for i := range volumes {
+		items[i] = VolumeItem{volume: volumes[i], containers: mountedBy[volumes[i].Name]}
+		sort.Strings(items[i].containers)
+	}
+	m.items = items
+}
+
+func (m VolumesListModel) ItemCount() int {
+	return len(m.items)
+}
+
+func (m VolumesListModel) Item(index int) ui.ListItem {
+	return &m.items[index]
+}
+func (m *VolumesListModel) ImagesUpdated() {
+}
+func (m *VolumesListModel) ContainersUpdated() {
+	m.Update()
+	m.NotifyChanged()
+}
+func (m *VolumesListModel) NetworksUpdated() {
+}
+func (m *VolumesListModel) VolumesUpdated() {
+	m.Update()
+	m.NotifyChanged()
+}
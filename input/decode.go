@@ -0,0 +1,194 @@
+package input
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eiannone/keyboard"
+)
+
+// Keys sent as ESC [ <final> or ESC O <final>
+var finalKeys = map[byte]keyboard.Key{
+	'A': keyboard.KeyArrowUp,
+	'B': keyboard.KeyArrowDown,
+	'C': keyboard.KeyArrowRight,
+	'D': keyboard.KeyArrowLeft,
+	'H': keyboard.KeyHome,
+	'F': keyboard.KeyEnd,
+	'P': keyboard.KeyF1,
+	'Q': keyboard.KeyF2,
+	'R': keyboard.KeyF3,
+	'S': keyboard.KeyF4,
+}
+
+// Keys sent as ESC [ <number> ~
+var tildeKeys = map[int]keyboard.Key{
+	1:  keyboard.KeyHome,
+	2:  keyboard.KeyInsert,
+	3:  keyboard.KeyDelete,
+	4:  keyboard.KeyEnd,
+	5:  keyboard.KeyPgup,
+	6:  keyboard.KeyPgdn,
+	7:  keyboard.KeyHome,
+	8:  keyboard.KeyEnd,
+	11: keyboard.KeyF1,
+	12: keyboard.KeyF2,
+	13: keyboard.KeyF3,
+	14: keyboard.KeyF4,
+	15: keyboard.KeyF5,
+	17: keyboard.KeyF6,
+	18: keyboard.KeyF7,
+	19: keyboard.KeyF8,
+	20: keyboard.KeyF9,
+	21: keyboard.KeyF10,
+	23: keyboard.KeyF11,
+	24: keyboard.KeyF12,
+}
+
+// Function keys on the linux console, sent as ESC [ [ <final>
+var linuxKeys = map[byte]keyboard.Key{
+	'A': keyboard.KeyF1,
+	'B': keyboard.KeyF2,
+	'C': keyboard.KeyF3,
+	'D': keyboard.KeyF4,
+	'E': keyboard.KeyF5,
+}
+
+/**
+	Decodes the first event in the buffer, returns the number of bytes
+	used, 0 if more input is needed to complete it. The event is nil
+	when the bytes are a sequence with no meaning here.
+**/
+func Decode(buffer []byte) (int, interface{}) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	if buffer[0] == '\u001b' {
+		if len(buffer) == 1 {
+			return 0, nil
+		}
+		switch buffer[1] {
+		case '[':
+			return decodeCSI(buffer)
+		case 'O':
+			if len(buffer) < 3 {
+				return 0, nil
+			}
+			if key, ok := finalKeys[buffer[2]]; ok {
+				return 3, KeyInputKey(key)
+			}
+			return 3, nil
+		case '\u001b':
+			return 1, KeyInputKey(keyboard.KeyEsc)
+		default:
+			// alt combinations are not handled
+			_, size := utf8.DecodeRune(buffer[1:])
+			return 1 + size, nil
+		}
+	}
+
+	if keyboard.Key(buffer[0]) <= keyboard.KeySpace || keyboard.Key(buffer[0]) == keyboard.KeyBackspace2 {
+		return 1, KeyInputKey(keyboard.Key(buffer[0]))
+	}
+
+	if !utf8.FullRune(buffer) {
+		return 0, nil
+	}
+	char, size := utf8.DecodeRune(buffer)
+	return size, KeyInputChar(char)
+}
+
+/**
+	Decodes what the buffer has so far, for when no more input came in time
+**/
+func DecodePending(buffer []byte) (int, interface{}) {
+	if buffer[0] == '\u001b' {
+		return 1, KeyInputKey(keyboard.KeyEsc)
+	}
+	return 1, nil
+}
+
+func decodeCSI(buffer []byte) (int, interface{}) {
+	if len(buffer) < 3 {
+		return 0, nil
+	}
+
+	if buffer[2] == '[' {
+		if len(buffer) < 4 {
+			return 0, nil
+		}
+		if key, ok := linuxKeys[buffer[3]]; ok {
+			return 4, KeyInputKey(key)
+		}
+		return 4, nil
+	}
+
+	var end = 2
+	for ; end < len(buffer); end++ {
+		if buffer[end] >= 0x40 && buffer[end] <= 0x7E {
+			break
+		}
+	}
+	if end == len(buffer) {
+		return 0, nil
+	}
+
+	var params = string(buffer[2:end])
+	var final = buffer[end]
+	var size = end + 1
+
+	if strings.HasPrefix(params, "<") && (final == 'M' || final == 'm') {
+		mouse, ok := decodeSGRMouse(params[1:], final == 'M')
+		if ok {
+			return size, mouse
+		}
+		return size, nil
+	}
+
+	if final == '~' {
+		number, _ := strconv.Atoi(strings.Split(params, ";")[0])
+		if key, ok := tildeKeys[number]; ok {
+			return size, KeyInputKey(key)
+		}
+		return size, nil
+	}
+
+	if key, ok := finalKeys[final]; ok {
+		return size, KeyInputKey(key)
+	}
+	return size, nil
+}
+
+// SGR mouse reports are ESC [ < button ; x ; y M, or m when released
+func decodeSGRMouse(params string, pressed bool) (MouseInput, bool) {
+	var fields = strings.Split(params, ";")
+	if len(fields) != 3 {
+		return MouseInput{}, false
+	}
+
+	var values [3]int
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return MouseInput{}, false
+		}
+		values[i] = value
+	}
+
+	var code = values[0]
+	var mouse = MouseInput{X: uint16(values[1]), Y: uint16(values[2]), Pressed: pressed}
+
+	switch {
+	case code&64 != 0 && code&1 == 0:
+		mouse.Button = MouseWheelUp
+	case code&64 != 0:
+		mouse.Button = MouseWheelDown
+	case code&32 != 0:
+		mouse.Button = MouseMotion
+	default:
+		mouse.Button = MouseButton(code & 3)
+	}
+	return mouse, true
+}
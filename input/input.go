@@ -1,7 +1,10 @@
 package input
 
 import (
+	"errors"
 	"log"
+	"os"
+	"time"
 
 	"github.com/eiannone/keyboard"
 )
@@ -31,39 +34,111 @@ func (k KeyInput) GetChar() rune {
 	return k.char
 }
 
+// Time to wait for the rest of an escape sequence before taking it as ESC
+const EscapeTimeout = 50 * time.Millisecond
+
+/**
+	Reads the terminal and turns what is typed or clicked
+	into KeyInput and MouseInput events
+**/
 type InputHandler struct {
-	active  bool
-	channel chan KeyInput
+	channel  chan interface{}
+	terminal *Terminal
+	clicks   ClickTracker
 }
 
 func InputHandlerNew() *InputHandler {
-	handler := InputHandler{active: true, channel: make(chan KeyInput, 100)}
-	go handler.RunCheck()
+	handler := InputHandler{channel: make(chan interface{}, 100)}
+	handler.Resume()
 	return &handler
 }
 
-func (i *InputHandler) RunCheck() {
-	keyboard.Open()
-	defer keyboard.Close()
-	for i.active {
-		input, key, err := keyboard.GetKey()
-		if err == nil {
-			i.channel <- KeyInput{key: key, char: input}
-		} else {
-			log.Print(err)
+/**
+	Gives the terminal back, for running other programs
+**/
+func (i *InputHandler) Suspend() {
+	if i.terminal != nil {
+		i.terminal.MouseOff()
+		i.terminal.Close()
+		i.terminal = nil
+	}
+}
+
+func (i *InputHandler) Resume() {
+	if i.terminal != nil {
+		return
+	}
+	terminal, err := TerminalOpen()
+	if err != nil {
+		log.Print("Error opening terminal ", err)
+		return
+	}
+	terminal.MouseOn()
+	i.terminal = terminal
+	go i.RunCheck(terminal)
+}
+
+func (i *InputHandler) RunCheck(terminal *Terminal) {
+	var buffer = make([]byte, 256)
+	var pending []byte
+
+	for {
+		var timeout time.Duration
+		if len(pending) > 0 {
+			timeout = EscapeTimeout
+		}
+		// Reading goes on without the timeout, a closed terminal ends it
+		if err := terminal.SetReadTimeout(timeout); err != nil && !terminal.Closed() {
+			log.Print("Error setting terminal read timeout ", err)
+		}
+
+		n, err := terminal.Read(buffer)
+		pending = i.Decode(append(pending, buffer[:n]...), false)
+
+		if err != nil {
+			if os.IsTimeout(err) {
+				pending = i.Decode(pending, true)
+			} else {
+				if !errors.Is(err, os.ErrClosed) {
+					log.Print("Error reading terminal ", err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// Sends the events in the buffer and returns what is left of it
+func (i *InputHandler) Decode(buffer []byte, flush bool) []byte {
+	for len(buffer) > 0 {
+		size, event := Decode(buffer)
+		if size == 0 {
+			if !flush {
+				break
+			}
+			size, event = DecodePending(buffer)
+		}
+		buffer = buffer[size:]
+
+		if mouse, ok := event.(MouseInput); ok {
+			i.channel <- i.clicks.Track(mouse)
+		} else if event != nil {
+			i.channel <- event
 		}
 	}
+	return buffer
 }
 
-func (i *InputHandler) GetKeyInput() (KeyInput, bool) {
+/**
+	Returns the next KeyInput or MouseInput, if there is any
+**/
+func (i *InputHandler) GetEvent() (interface{}, bool) {
 	if len(i.channel) > 0 {
 		return <-i.channel, true
 	}
-	return KeyInput{}, false
+	return nil, false
 }
 
 func (i *InputHandler) Close() {
-	keyboard.Close()
-	i.active = false
-	close(i.channel)
+	i.Suspend()
 }
@@ -0,0 +1,85 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eiannone/keyboard"
+)
+
+// Names for keys which are not plain characters, as written in the config file
+var keyNames = map[string]keyboard.Key{
+	"enter":     keyboard.KeyEnter,
+	"tab":       keyboard.KeyTab,
+	"esc":       keyboard.KeyEsc,
+	"space":     keyboard.KeySpace,
+	"backspace": keyboard.KeyBackspace2,
+	"delete":    keyboard.KeyDelete,
+	"insert":    keyboard.KeyInsert,
+	"home":      keyboard.KeyHome,
+	"end":       keyboard.KeyEnd,
+	"pgup":      keyboard.KeyPgup,
+	"pgdn":      keyboard.KeyPgdn,
+	"up":        keyboard.KeyArrowUp,
+	"down":      keyboard.KeyArrowDown,
+	"left":      keyboard.KeyArrowLeft,
+	"right":     keyboard.KeyArrowRight,
+	"f1":        keyboard.KeyF1,
+	"f2":        keyboard.KeyF2,
+	"f3":        keyboard.KeyF3,
+	"f4":        keyboard.KeyF4,
+	"f5":        keyboard.KeyF5,
+	"f6":        keyboard.KeyF6,
+	"f7":        keyboard.KeyF7,
+	"f8":        keyboard.KeyF8,
+	"f9":        keyboard.KeyF9,
+	"f10":       keyboard.KeyF10,
+	"f11":       keyboard.KeyF11,
+	"f12":       keyboard.KeyF12,
+}
+
+/**
+	Parses a key as written in the config file, either a single
+	character like "v" or "L", a name like "delete", "f5" or "pgup",
+	or a control key like "ctrl+r".
+**/
+func ParseKey(text string) (KeyInput, error) {
+	if utf8.RuneCountInString(text) == 1 {
+		char, _ := utf8.DecodeRuneInString(text)
+		if char == ' ' {
+			return KeyInputKey(keyboard.KeySpace), nil
+		}
+		return KeyInputChar(char), nil
+	}
+
+	var name = strings.ToLower(text)
+
+	if key, ok := keyNames[name]; ok {
+		return KeyInputKey(key), nil
+	}
+
+	if strings.HasPrefix(name, "ctrl+") && len(name) == 6 && name[5] >= 'a' && name[5] <= 'z' {
+		return KeyInputKey(keyboard.KeyCtrlA + keyboard.Key(name[5]-'a')), nil
+	}
+
+	return KeyInput{}, fmt.Errorf("unknown key '%s'", text)
+}
+
+/**
+	The name of a key, as ParseKey would read it
+**/
+func KeyName(key KeyInput) string {
+	if key.key == 0 {
+		return string(key.char)
+	}
+	for name, value := range keyNames {
+		if value == key.key {
+			return name
+		}
+	}
+	if key.key >= keyboard.KeyCtrlA && key.key <= keyboard.KeyCtrlZ {
+		return "ctrl+" + string(rune('a'+key.key-keyboard.KeyCtrlA))
+	}
+	return fmt.Sprintf("key %d", key.key)
+}
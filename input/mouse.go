@@ -0,0 +1,56 @@
+package input
+
+import (
+	"time"
+)
+
+type MouseButton int
+
+const (
+	MouseLeft MouseButton = iota
+	MouseMiddle
+	MouseRight
+	MouseNone
+	MouseWheelUp
+	MouseWheelDown
+	MouseMotion
+)
+
+// Two clicks on the same cell within this time make a double click
+const DoubleClickTime = 400 * time.Millisecond
+
+/**
+	A mouse event, coordinates start at 1 like screen positions
+**/
+type MouseInput struct {
+	Button      MouseButton
+	Pressed     bool
+	DoubleClick bool
+	X           uint16
+	Y           uint16
+}
+
+func (m MouseInput) IsClick() bool {
+	return m.Button == MouseLeft && m.Pressed
+}
+
+/**
+	Tells double clicks apart from single ones
+**/
+type ClickTracker struct {
+	last     MouseInput
+	lastTime time.Time
+}
+
+func (c *ClickTracker) Track(mouse MouseInput) MouseInput {
+	if !mouse.IsClick() {
+		return mouse
+	}
+	var now = time.Now()
+	mouse.DoubleClick = !c.last.DoubleClick &&
+		c.last.X == mouse.X && c.last.Y == mouse.Y &&
+		now.Sub(c.lastTime) < DoubleClickTime
+	c.last = mouse
+	c.lastTime = now
+	return mouse
+}
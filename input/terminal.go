@@ -0,0 +1,165 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/clidockermgr/util"
+	"golang.org/x/sys/unix"
+)
+
+/**
+	The controlling terminal, put in raw mode while reading input.
+	Reads time out with deadlines when the terminal can be polled,
+	or with VMIN and VTIME otherwise.
+**/
+type Terminal struct {
+	file      *os.File
+	original  unix.Termios
+	raw       unix.Termios
+	deadlines bool
+	closed    int32
+}
+
+// Longest wait of reads with no timeout when deadlines are not supported, in tenths of a second
+const termiosReadInterval = 1
+
+func TerminalOpen() (*Terminal, error) {
+	file, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var terminal = Terminal{file: file, deadlines: true}
+
+	original, err := terminal.getTermios()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	terminal.original = *original
+
+	var raw = *original
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP |
+		unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	if err = terminal.setTermios(&raw); err != nil {
+		file.Close()
+		return nil, err
+	}
+	terminal.raw = raw
+	return &terminal, nil
+}
+
+/**
+	The ioctls go through SyscallConn, Fd would put the
+	file in blocking mode where deadlines never fire
+**/
+func (t *Terminal) getTermios() (*unix.Termios, error) {
+	var termios *unix.Termios
+	var ioctlErr error
+
+	conn, err := t.file.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	err = conn.Control(func(fd uintptr) {
+		termios, ioctlErr = unix.IoctlGetTermios(int(fd), ioctlGetTermios)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return termios, ioctlErr
+}
+
+func (t *Terminal) setTermios(termios *unix.Termios) error {
+	var ioctlErr error
+
+	conn, err := t.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	err = conn.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetTermios(int(fd), ioctlSetTermios, termios)
+	})
+	if err != nil {
+		return err
+	}
+	return ioctlErr
+}
+
+/**
+	Reads what is typed, a read which times out returns an
+	error for which os.IsTimeout is true
+**/
+func (t *Terminal) Read(buffer []byte) (int, error) {
+	n, err := t.file.Read(buffer)
+	if n == 0 && err == io.EOF && !t.deadlines {
+		// VTIME ran out before anything was typed
+		return 0, os.ErrDeadlineExceeded
+	}
+	return n, err
+}
+
+/**
+	A zero timeout waits for input with no limit. Without deadlines
+	reads still return every termiosReadInterval, so that the reader
+	notices when the terminal gets closed.
+**/
+func (t *Terminal) SetReadTimeout(timeout time.Duration) error {
+	if t.deadlines {
+		var deadline time.Time
+		if timeout != 0 {
+			deadline = time.Now().Add(timeout)
+		}
+		err := t.file.SetReadDeadline(deadline)
+		if !errors.Is(err, os.ErrNoDeadline) {
+			return err
+		}
+		t.deadlines = false
+	}
+
+	var tenths = termiosReadInterval
+	if timeout != 0 {
+		tenths = int((timeout + 100*time.Millisecond - 1) / (100 * time.Millisecond))
+	}
+	var raw = t.raw
+	raw.Cc[unix.VMIN] = 0
+	raw.Cc[unix.VTIME] = uint8(util.Max(1, util.Min(tenths, 255)))
+	if raw.Cc == t.raw.Cc {
+		return nil
+	}
+	if err := t.setTermios(&raw); err != nil {
+		return err
+	}
+	t.raw = raw
+	return nil
+}
+
+// Turns on xterm mouse reporting, in SGR format
+func (t *Terminal) MouseOn() {
+	t.file.WriteString("\u001b[?1000h\u001b[?1006h")
+}
+
+func (t *Terminal) MouseOff() {
+	t.file.WriteString("\u001b[?1006l\u001b[?1000l")
+}
+
+// Closing also ends a read waiting in another goroutine
+func (t *Terminal) Close() {
+	atomic.StoreInt32(&t.closed, 1)
+	t.setTermios(&t.original)
+	t.file.Close()
+}
+
+func (t *Terminal) Closed() bool {
+	return atomic.LoadInt32(&t.closed) == 1
+}
@@ -0,0 +1,12 @@
+// +build darwin freebsd netbsd openbsd
+
+package input
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/ui"
+	"github.com/eiannone/keyboard"
+)
+
+func char(c rune) input.KeyInput {
+	return input.KeyInputChar(c)
+}
+
+func key(k keyboard.Key) input.KeyInput {
+	return input.KeyInputKey(k)
+}
+
+/**
+	Defines the actions of the views with their default keys,
+	the config file can map them to other keys by name.
+**/
+func DefineActions(keymap *ui.Keymap) {
+	keymap.Define("app.context", "Switches to another docker context", char('C'))
+
+	keymap.DefineSection("container", "Containers view")
+	keymap.Reserve("container", "moving the selection", ui.ListKeys...)
+	keymap.Define("container.inspect", "Displays container information", char('v'))
+	keymap.Define("container.details", "Displays container details, or expands or collapses a compose project", char('d'), key(keyboard.KeyEnter))
+	keymap.Define("container.shell", "Opens a shell in a container", char('s'))
+	keymap.Define("container.bash", "Opens a bash shell in a container, if command is present", char('b'))
+	keymap.Define("container.logs", "Shows container log, or the merged logs of a compose project, following new output while at the bottom", char('l'))
+	keymap.Define("container.log-options", "Asks for the log options, then shows the container log or the merged logs of a compose project", char('L'))
+	keymap.Define("container.start", "Starts a container", char('S'))
+	keymap.Define("container.stop", "Stops a container or a compose project, killing containers not stopping within the stop timeout", char('x'))
+	keymap.Define("container.restart", "Restarts a container, or all containers of a compose project", char('r'))
+	keymap.Define("container.pause", "Pauses a container", char('p'))
+	keymap.Define("container.unpause", "Unpauses a container", char('u'))
+	keymap.Define("container.kill", "Sends a signal to a container, picked from a list or typed by name or number", char('k'))
+	keymap.Define("container.delete", "Deletes a container or a compose project, optionally forcing it and removing anonymous volumes", key(keyboard.KeyDelete))
+	keymap.Define("container.only-active", "Shows only running containers, or all of them", char('a'))
+	keymap.Define("container.sort", "Sorts containers by the next column: name, image, status, created, cpu, memory, disk usage, network or block i/o", char('o'))
+	keymap.Define("container.sort-order", "Sorts containers in ascending or descending order", char('O'))
+	keymap.Define("container.dashboard", "Shows charts of the CPU, memory and i/o of the last two minutes", char('D'))
+	keymap.Define("container.io", "Shows or hides the network and block i/o rates", char('i'))
+	keymap.Define("container.group", "Groups containers by compose project, or shows them in a flat list", char('g'))
+
+	keymap.DefineSection("image", "Images view")
+	keymap.Reserve("image", "moving the selection", ui.ListKeys...)
+	keymap.Define("image.shell", "Creates a container and runs shell for a given image", char('s'))
+	keymap.Define("image.bash", "Creates a container and runs bash shell for a given image if command is present", char('b'))
+	keymap.Define("image.inspect", "Displays image information", char('v'), key(keyboard.KeyEnter))
+	keymap.Define("image.pull", "Pulls an image from a registry, showing the progress of each layer", char('p'))
+	keymap.Define("image.build", "Builds an image from a Dockerfile, showing the build output", char('B'))
+	keymap.Define("image.delete", "Deletes an image", key(keyboard.KeyDelete))
+	keymap.Define("image.sort", "Sorts images by the next column: age, size or name", char('o'))
+	keymap.Define("image.sort-order", "Sorts images in ascending or descending order", char('O'))
+
+	keymap.DefineSection("volume", "Volumes view")
+	keymap.Reserve("volume", "moving the selection", ui.ListKeys...)
+	keymap.Define("volume.inspect", "Displays volume information", char('v'), key(keyboard.KeyEnter))
+	keymap.Define("volume.delete", "Deletes a volume", key(keyboard.KeyDelete))
+
+	keymap.DefineSection("network", "Networks view")
+	keymap.Reserve("network", "moving the selection", ui.ListKeys...)
+	keymap.Define("network.expand", "Expands or collapses a network, showing its attached containers", key(keyboard.KeyEnter))
+	keymap.Define("network.inspect", "Displays network information", char('v'))
+	keymap.Define("network.create", "Creates a network", char('n'))
+	keymap.Define("network.delete", "Deletes a network", key(keyboard.KeyDelete))
+	keymap.Define("network.connect", "Connects the container selected in the containers view to a network", char('c'))
+	keymap.Define("network.disconnect", "Disconnects the selected attached container, or the container selected in the containers view, from a network", char('x'))
+
+	keymap.DefineSection("logs", "Logs popup")
+	keymap.Reserve("logs", "scrolling and searching", ui.TextViewKeys...)
+	keymap.Define("logs.streams", "Cycles between showing all output, only stdout or only stderr", char('o'))
+	keymap.Define("logs.timestamps", "Shows or hides timestamps, in local time", char('t'))
+	keymap.Define("logs.tail", "Cycles the number of last lines loaded: all, 10, 100, 1000", char('T'))
+	keymap.Define("logs.since", "Cycles showing logs since: any time, 10m, 1h, 24h ago", char('S'))
+	keymap.Define("logs.until", "Cycles showing logs until: now, 10m, 1h, 24h ago", char('U'))
+	keymap.Define("logs.options", "Asks for the log options, allowing any time or number of lines", char('O'))
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acarl005/stripansi"
+	"github.com/clidockermgr/docker"
+	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/ui"
+	"github.com/clidockermgr/util"
+	"github.com/docker/docker/api/types"
+	timetypes "github.com/docker/docker/api/types/time"
+)
+
+const (
+	LogStreamsAll = iota
+	LogStreamsStdout
+	LogStreamsStderr
+)
+
+const LogTimeFormat = "2006-01-02 15:04:05.000"
+
+var LogStreamTitles = []string{"Logs", "Logs (stdout only)", "Logs (stderr only)"}
+
+// Presets cycled by the logs popup keys, the first one means no limit
+var LogTailPresets = []string{"", "10", "100", "1000"}
+var LogTimePresets = []string{"", "10m", "1h", "24h"}
+
+func StderrStyle() {
+	ui.CurrentTheme.Stderr.Apply()
+}
+
+func NextPreset(presets []string, current string) string {
+	for i := range presets {
+		if presets[i] == current {
+			return presets[(i+1)%len(presets)]
+		}
+	}
+	return presets[0]
+}
+
+/**
+	A container whose logs are shown, the name
+	prefixes its lines when merging several logs
+**/
+type LogSource struct {
+	ContainerId string
+	Name        string
+}
+
+func ContainerLogSources(container *types.Container) []LogSource {
+	return []LogSource{{ContainerId: container.ID, Name: ContainerName(container)}}
+}
+
+/**
+	The containers of a compose project, named after their services
+**/
+func ProjectLogSources(project *docker.ComposeProject) []LogSource {
+	var sources []LogSource
+	for i := range project.Containers {
+		var name = docker.ComposeService(&project.Containers[i])
+		if name == "" {
+			name = ContainerName(&project.Containers[i])
+		}
+		sources = append(sources, LogSource{ContainerId: project.Containers[i].ID, Name: name})
+	}
+	return sources
+}
+
+/**
+	Keeps the lines received from the log streams so
+	they can be filtered by stream at any time
+**/
+type LogViewer struct {
+	mutex      sync.Mutex
+	client     *docker.ServiceHandler
+	sources    []LogSource
+	nameWidth  int
+	options    docker.LogOptions
+	cancels    []context.CancelFunc
+	generation int
+	lines      []docker.LogLine
+	streams    int
+	textView   *ui.TextView
+	container  *ui.TitledContainer
+}
+
+func LogViewerNew(app *ui.Application, client *docker.ServiceHandler, sources []LogSource, options docker.LogOptions) *LogViewer {
+	var viewer = LogViewer{client: client, sources: sources, options: options, textView: ui.TextViewNew("")}
+	for i := range sources {
+		viewer.nameWidth = util.Max(viewer.nameWidth, len(sources[i].Name))
+	}
+	viewer.textView.SetFollow(true)
+	viewer.container = MakeTextPopup(LogStreamTitles[LogStreamsAll], viewer.textView)
+
+	app.Keymap().Bind(viewer.textView, "logs.streams", func(input.KeyInput) {
+		viewer.CycleStreams()
+	})
+	app.Keymap().Bind(viewer.textView, "logs.timestamps", func(input.KeyInput) {
+		viewer.ChangeOptions(func(options *docker.LogOptions) {
+			options.Timestamps = !options.Timestamps
+		})
+	})
+	app.Keymap().Bind(viewer.textView, "logs.tail", func(input.KeyInput) {
+		viewer.ChangeOptions(func(options *docker.LogOptions) {
+			options.Tail = NextPreset(LogTailPresets, options.Tail)
+		})
+	})
+	app.Keymap().Bind(viewer.textView, "logs.since", func(input.KeyInput) {
+		viewer.ChangeOptions(func(options *docker.LogOptions) {
+			options.Since = NextPreset(LogTimePresets, options.Since)
+		})
+	})
+	app.Keymap().Bind(viewer.textView, "logs.until", func(input.KeyInput) {
+		viewer.ChangeOptions(func(options *docker.LogOptions) {
+			options.Until = NextPreset(LogTimePresets, options.Until)
+		})
+	})
+	app.Keymap().Bind(viewer.textView, "logs.options", func(input.KeyInput) {
+		ShowLogOptions(app, client, sources, viewer.Options())
+	})
+	return &viewer
+}
+
+func (l *LogViewer) Options() docker.LogOptions {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.options
+}
+
+/**
+	Changes the options under the lock, as lines keep being
+	written with them, then restarts streaming
+**/
+func (l *LogViewer) ChangeOptions(change func(options *docker.LogOptions)) {
+	l.mutex.Lock()
+	change(&l.options)
+	l.mutex.Unlock()
+
+	l.Start()
+}
+
+/**
+	(Re)starts streaming with the current options, lines
+	still arriving from a previous stream are dropped.
+	Lines of several containers are merged as they arrive.
+**/
+func (l *LogViewer) Start() {
+	l.Stop()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.generation++
+	l.lines = nil
+	l.textView.Clear()
+	l.textView.SetFollow(true)
+	l.UpdateTitle()
+
+	var generation = l.generation
+	if len(l.sources) > 1 {
+		ctx, cancel := context.WithCancel(context.Background())
+		l.cancels = append(l.cancels, cancel)
+		go l.Merge(ctx, generation, l.options)
+		return
+	}
+	for i := range l.sources {
+		var source = l.sources[i]
+		l.cancels = append(l.cancels, l.client.FollowLogs(source.ContainerId, l.options, func(line docker.LogLine) {
+			line.Source = source.Name
+			l.Add(generation, line)
+		}))
+	}
+}
+
+/**
+	Loads the current logs of all the sources and writes them sorted
+	by time, then follows each source from its last line on. Timestamps
+	are always requested to sort by them, and shown only if asked for.
+**/
+func (l *LogViewer) Merge(ctx context.Context, generation int, options docker.LogOptions) {
+	var backlog = options
+	backlog.Timestamps = true
+	backlog.NoFollow = true
+
+	// Sources without lines are followed from the time the backlog was asked
+	// for, so that lines written while it is read are not lost
+	start, err := l.client.DaemonTime(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Print("Error getting the daemon time ", err)
+	}
+
+	var mutex sync.Mutex
+	var group sync.WaitGroup
+	var lines []docker.LogLine
+	var last = make([]time.Time, len(l.sources))
+
+	for i := range l.sources {
+		var index = i
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			l.client.ReadLogs(ctx, l.sources[index].ContainerId, backlog, func(line docker.LogLine) {
+				line.Source = l.sources[index].Name
+				mutex.Lock()
+				lines = append(lines, line)
+				if line.Time.After(last[index]) {
+					last[index] = line.Time
+				}
+				mutex.Unlock()
+			})
+		}()
+	}
+	group.Wait()
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+	// Each source sent up to the last lines asked for, the merged logs keep as many
+	if tail, err := strconv.Atoi(options.Tail); err == nil && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	for i := range lines {
+		l.Add(generation, lines[i])
+	}
+
+	if options.Until != "" || ctx.Err() != nil {
+		return
+	}
+
+	// Daemon times, so that the local clock does not matter
+	for i := range l.sources {
+		var source = l.sources[i]
+		var follow = docker.LogOptions{Since: options.Since, Tail: "0", Timestamps: true}
+		if !last[i].IsZero() {
+			follow = docker.LogOptions{Since: docker.LogTime(last[i].Add(time.Nanosecond)), Timestamps: true}
+		} else if !start.IsZero() {
+			follow = docker.LogOptions{Since: docker.LogTime(start), Timestamps: true}
+		}
+		go l.client.ReadLogs(ctx, source.ContainerId, follow, func(line docker.LogLine) {
+			line.Source = source.Name
+			l.Add(generation, line)
+		})
+	}
+}
+
+func (l *LogViewer) Stop() {
+	for _, cancel := range l.cancels {
+		cancel()
+	}
+	l.cancels = nil
+}
+
+func (l *LogViewer) UpdateTitle() {
+	var title = LogStreamTitles[l.streams]
+	var options []string
+
+	if l.options.Tail != "" {
+		options = append(options, "tail "+l.options.Tail)
+	}
+	if l.options.Since != "" {
+		options = append(options, "since "+l.options.Since)
+	}
+	if l.options.Until != "" {
+		options = append(options, "until "+l.options.Until)
+	}
+	if len(options) > 0 {
+		title += " - " + strings.Join(options, ", ")
+	}
+	l.container.SetTitle(title)
+}
+
+func (l *LogViewer) Shows(line docker.LogLine) bool {
+	switch l.streams {
+	case LogStreamsStdout:
+		return !line.Stderr
+	case LogStreamsStderr:
+		return line.Stderr
+	}
+	return true
+}
+
+func (l *LogViewer) Write(line docker.LogLine) {
+	var text = stripansi.Strip(line.Text)
+
+	if l.options.Timestamps && !line.Time.IsZero() {
+		text = line.Time.Format(LogTimeFormat) + " " + text
+	}
+	if len(l.sources) > 1 {
+		text = fmt.Sprintf("%-*s | %s", l.nameWidth, line.Source, text)
+	}
+
+	if line.Stderr {
+		l.textView.AppendStyled(text, StderrStyle)
+	} else {
+		l.textView.Append(text)
+	}
+}
+
+func (l *LogViewer) Add(generation int, line docker.LogLine) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if generation != l.generation {
+		return
+	}
+
+	l.lines = append(l.lines, line)
+	if l.Shows(line) {
+		l.Write(line)
+	}
+}
+
+func (l *LogViewer) CycleStreams() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.streams = (l.streams + 1) % len(LogStreamTitles)
+	l.UpdateTitle()
+
+	l.textView.Clear()
+	for i := range l.lines {
+		if l.Shows(l.lines[i]) {
+			l.Write(l.lines[i])
+		}
+	}
+}
+
+func ValidateTail(value interface{}) error {
+	if value == "" || value == "all" {
+		return nil
+	}
+	_, err := strconv.ParseUint(value.(string), 10, 32)
+	if err != nil {
+		return errors.New("must be a number of lines or all")
+	}
+	return nil
+}
+
+func ValidateLogTime(value interface{}) error {
+	if value != "" {
+		_, err := timetypes.GetTimestamp(value.(string), time.Now())
+		return err
+	}
+	return nil
+}
+
+var LogTimeHistory = ui.InputHistoryNew()
+
+/**
+	Asks for the log options, then shows the logs
+**/
+func ShowLogOptions(app *ui.Application, client *docker.ServiceHandler, sources []LogSource, options docker.LogOptions) {
+	var form = ui.FormNew()
+	form.AddTextField("tail", "Last lines", options.Tail, nil, ValidateTail)
+	form.AddTextField("since", "Since", options.Since, LogTimeHistory, ValidateLogTime)
+	form.AddTextField("until", "Until", options.Until, LogTimeHistory, ValidateLogTime)
+	form.AddCheckbox("timestamps", "Timestamps", options.Timestamps)
+
+	app.ShowDialog(ui.DialogNew("Log options, times like 10m or 2006-01-02T15:04:05", form, 70, func(result ui.FormResult) {
+		var tail = result.String("tail")
+		if tail == "all" {
+			tail = ""
+		}
+		ShowLogSources(app, client, sources, docker.LogOptions{
+			Tail:       tail,
+			Since:      result.String("since"),
+			Until:      result.String("until"),
+			Timestamps: result.Bool("timestamps"),
+		})
+	}))
+}
+
+func ShowLogs(app *ui.Application, client *docker.ServiceHandler, containerId string, options docker.LogOptions) {
+	ShowLogSources(app, client, []LogSource{{ContainerId: containerId}}, options)
+}
+
+/**
+	Shows the logs of all the containers of a project merged
+**/
+func ShowProjectLogs(app *ui.Application, client *docker.ServiceHandler, project *docker.ComposeProject, options docker.LogOptions) {
+	ShowLogSources(app, client, ProjectLogSources(project), options)
+}
+
+func ShowLogSources(app *ui.Application, client *docker.ServiceHandler, sources []LogSource, options docker.LogOptions) {
+	var viewer = LogViewerNew(app, client, sources, options)
+
+	viewer.Start()
+
+	ShowTextContainer(app, viewer.container, viewer.Stop)
+}
@@ -1,55 +1,73 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"math"
+	"net"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/acarl005/stripansi"
+	"github.com/clidockermgr/config"
 	"github.com/clidockermgr/docker"
 	"github.com/clidockermgr/input"
 	"github.com/clidockermgr/ui"
+	"github.com/clidockermgr/util"
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/client"
-	"github.com/eiannone/keyboard"
 )
 
-const HelpText = `Keys:
-
-    tab: Switch focus between UI elements
-    ESC: Closes active popup, or exits the application
-	h: Shows this help
-    Container view:
-        v: Displays container information
-		d: Displays container details
-        s: Opens a shell in a container
-        b: Opens a bash shell in a container, if command is present
-        l: Shows container log
-        k: Kills a container
-        delete: Deletes a container
-    Images view:
-        s: Creates a container and runs shell for a given image
-        b: Creates a container and runs bash shell for a given image if command is present
-        v: Displays image information
-        delete: Deletes an image
+// Keys handled inside popups and dialogs, which are not remapped
+const PopupHelpText = `    Mouse:
+        Click to focus a view and select a row, double click or enter
+        for the default action, the wheel scrolls lists and text popups
+    Text popups:
+        arrows, PgUp, PgDn: Scroll the text
+        Home: Goes to the top
+        End: Goes to the bottom and follows new output
+        /: Searches forward, ?: Searches backward
+            while typing, ctrl+r toggles regular expressions, ctrl+e toggles ignoring case,
+            enter confirms and ESC cancels the search
+        n: Goes to the next match, N: Goes to the previous match
+    Confirmations:
+        y: Confirms, n or ESC: Cancels
+        left, right: Move between the yes and no buttons, enter presses the focused one
+        tab: Moves between options, space toggles them
+    Dialogs:
+        tab: Moves to the next field
+        up, down: Go through previously entered values
+        space: Toggles checkboxes and opens dropdowns, left and right change the option
+        enter: Accepts, ESC: Cancels
 `
 
-func ShowTextPopup(app *ui.Application, title string, text string) {
+func MakeTextPopup(title string, textView *ui.TextView) *ui.TitledContainer {
+	container := ui.TitledContainerNew(title, textView, true)
+	container.Border = ui.LineBorder
 
-	maxWidth, maxHeight := ui.ScreenSize()
+	return container
+}
 
-	popupWidth := uint16(float32(maxWidth) * 0.75)
-	popupHeight := uint16(float32(maxHeight) * 0.8)
+// Text popups take most of the screen
+func TextPopupLayout(container *ui.TitledContainer) ui.LayoutHandler {
+	return func(maxWidth, maxHeight uint16) {
+		popupWidth := uint16(float32(maxWidth) * 0.75)
+		popupHeight := uint16(float32(maxHeight) * 0.8)
 
-	textView := ui.TextViewNew(text)
-	container := ui.TitledContainerNew(title, textView, true)
-	container.SetRect(ui.RectNew((maxWidth-popupWidth)/2, (maxHeight-popupHeight)/2, popupWidth, popupHeight))
-	container.Border = ui.LineBorder
+		container.SetRect(ui.RectNew((maxWidth-popupWidth)/2, (maxHeight-popupHeight)/2, popupWidth, popupHeight))
+	}
+}
+
+func ShowTextContainer(app *ui.Application, container *ui.TitledContainer, handler ui.CloseHandler) {
+	app.ShowPopupWithLayout(container, TextPopupLayout(container), handler)
+}
 
-	app.ShowPopup(container)
+func ShowTextPopup(app *ui.Application, title string, text string) {
+	ShowTextContainer(app, MakeTextPopup(title, ui.TextViewNew(text)), nil)
 }
 
 func ShowContainerInspect(app *ui.Application, client *docker.ServiceHandler, containerId string) {
@@ -77,42 +95,213 @@ func MakeContainerDetailsString(container types.ContainerJSON) string {
 	return str
 }
 
+// Samples per line of the CPU history in the details
+const CPUHistoryColumns = 10
+
+/**
+	Summarizes the CPU samples of a container, then lists them
+	oldest first, one per stats interval
+**/
+func MakeCPUHistoryString(history []float64) string {
+	if len(history) == 0 {
+		return "CPU          : no samples, the container is not running\n"
+	}
+
+	var total, peak float64
+	for _, value := range history {
+		total += value
+		peak = math.Max(peak, value)
+	}
+
+	var str = fmt.Sprintf("CPU          : %s now, %s average, %s peak over the last %s\n",
+		util.FormatPercent(history[len(history)-1]), util.FormatPercent(total/float64(len(history))),
+		util.FormatPercent(peak), time.Duration(len(history))*docker.StatsInterval)
+
+	str += "CPU history  :\n"
+	for i := 0; i < len(history); i += CPUHistoryColumns {
+		var line []string
+		for _, value := range history[i:util.Min(i+CPUHistoryColumns, len(history))] {
+			line = append(line, fmt.Sprintf("%7s", util.FormatPercent(value)))
+		}
+		str += "   " + strings.Join(line, "") + "\n"
+	}
+	return str
+}
+
 func ShowContainerDetails(app *ui.Application, client *docker.ServiceHandler, containerId string) {
 	result := client.InspectContainerRaw(containerId)
 
-	ShowTextPopup(app, "Container Details", MakeContainerDetailsString(result))
+	ShowTextPopup(app, "Container Details", MakeContainerDetailsString(result)+
+		MakeCPUHistoryString(client.CPUHistory(containerId)))
 }
 
 func ShowHelp(app *ui.Application) {
-	ShowTextPopup(app, "Help", HelpText)
+	ShowTextPopup(app, "Help", "Keys, action names in brackets can be mapped in "+*configPath+"\n\n"+
+		app.Keymap().Help()+PopupHelpText)
 }
 
-func RunCommand(command string, args ...string) {
+/**
+	Binds the actions available from every view
+**/
+func BindAppActions(app *ui.Application, view ui.View) {
+	var keymap = app.Keymap()
+
+	keymap.Bind(view, "app.help", func(input.KeyInput) {
+		ShowHelp(app)
+	})
+	keymap.Bind(view, "app.context", func(input.KeyInput) {
+		ShowContextPicker(app)
+	})
+}
+
+func RunCommand(app *ui.Application, command string, args ...string) {
 	var cmd = exec.Command(command, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	ui.CursorOn()
-	ui.ClearScreen()
+	app.Suspend()
 	cmd.Run()
-	ui.CursorOff()
+	app.Resume()
+}
+
+func DoExecContainer(app *ui.Application, containerId string, command string) {
+	DockerCommand(app, "exec", "-it", containerId, command)
 }
 
-func DoExecContainer(containerId string, command string) {
-	RunCommand("docker", "exec", "-it", containerId, command)
+func ExecShell(app *ui.Application, containerId string) {
+	DoExecContainer(app, containerId, "sh")
 }
 
-func ExecShell(containerId string) {
-	DoExecContainer(containerId, "sh")
+func ExecBashShell(app *ui.Application, containerId string) {
+	DoExecContainer(app, containerId, "bash")
 }
 
-func ExecBashShell(containerId string) {
-	DoExecContainer(containerId, "bash")
+func ContainerName(container *types.Container) string {
+	if len(container.Names) > 0 {
+		return strings.TrimPrefix(container.Names[0], "/")
+	}
+	return container.ID[0:12]
+}
+
+/**
+	Runs a container action in background, reporting
+	its result in the status bar once finished
+**/
+func RunContainerAction(app *ui.Application, container *types.Container, action string, done string, handler func(string) error) {
+	var name = ContainerName(container)
+	app.ShowMessage(fmt.Sprintf("%s container %s...", action, name))
+
+	go func() {
+		err := handler(container.ID)
+		if err != nil {
+			app.ShowError(fmt.Sprintf("%s container %s failed: %s", action, name, err))
+		} else {
+			app.ShowMessage(fmt.Sprintf("Container %s %s", name, done))
+		}
+	}()
+}
+
+var Signals = []string{"SIGTERM", "SIGINT", "SIGHUP", "SIGUSR1", "SIGUSR2", "SIGQUIT", "SIGKILL"}
+
+// Signals usually handled by processes without exiting, sent without confirmation
+var HarmlessSignals = []string{"SIGHUP", "HUP", "1", "SIGUSR1", "USR1", "10", "SIGUSR2", "USR2", "12", "SIGWINCH", "WINCH", "28"}
+
+func IsHarmlessSignal(signal string) bool {
+	for i := range HarmlessSignals {
+		if strings.EqualFold(signal, HarmlessSignals[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func ShowSignalPicker(app *ui.Application, client *docker.ServiceHandler, container *types.Container) {
+	ShowPicker(app, "Signal to send to "+ContainerName(container)+", pick or type one", Signals, func(signal string) {
+		app.ClosePopup()
+
+		var send = func(ui.FormResult) {
+			RunContainerAction(app, container, "Sending "+signal+" to", "received "+signal, func(containerId string) error {
+				return client.KillContainer(containerId, signal)
+			})
+		}
+
+		if IsHarmlessSignal(signal) {
+			send(nil)
+		} else {
+			app.ShowConfirm(ui.ConfirmNew("Send signal",
+				fmt.Sprintf("Send %s to container %s (%s)?", signal, ContainerName(container), container.ID[0:12]), send))
+		}
+	})
 }
 
-func ShowLogs(app *ui.Application, client *docker.ServiceHandler, containerId string) {
-	logs := client.Logs(containerId)
-	ShowTextPopup(app, "Logs", stripansi.Strip(logs))
+/**
+	Runs a container action on every container of a project in
+	background, reporting the result once all of them are done
+**/
+func RunProjectAction(app *ui.Application, project *docker.ComposeProject, action string, done string, handler func(string) error) {
+	app.ShowMessage(fmt.Sprintf("%s project %s...", action, project.Name))
+
+	go func() {
+		var wait sync.WaitGroup
+		var mutex sync.Mutex
+		var failed []string
+
+		for i := range project.Containers {
+			wait.Add(1)
+			go func(container *types.Container) {
+				defer wait.Done()
+				if err := handler(container.ID); err != nil {
+					log.Print("Error ", action, " container ", container.ID, ": ", err)
+					mutex.Lock()
+					failed = append(failed, fmt.Sprintf("%s: %s", ContainerName(container), err))
+					mutex.Unlock()
+				}
+			}(&project.Containers[i])
+		}
+		wait.Wait()
+
+		if len(failed) > 0 {
+			app.ShowError(fmt.Sprintf("%s project %s failed for %s", action, project.Name, strings.Join(failed, ", ")))
+		} else {
+			app.ShowMessage(fmt.Sprintf("Project %s %s", project.Name, done))
+		}
+	}()
+}
+
+func ConfirmRemoveProject(app *ui.Application, client *docker.ServiceHandler, project *docker.ComposeProject) {
+	var confirm = ui.ConfirmNew("Delete project",
+		fmt.Sprintf("Delete the %d containers of project %s?", len(project.Containers), project.Name),
+		func(options ui.FormResult) {
+			RunProjectAction(app, project, "Deleting", "deleted", func(containerId string) error {
+				return client.RemoveContainer(containerId, options.Bool("force"), options.Bool("volumes"))
+			})
+		})
+	confirm.AddOption("force", "Force removal, killing running containers", false)
+	confirm.AddOption("volumes", "Remove their anonymous volumes", false)
+
+	app.ShowConfirm(confirm)
+}
+
+func ConfirmRemoveContainer(app *ui.Application, client *docker.ServiceHandler, container *types.Container) {
+	var confirm = ui.ConfirmNew("Delete container",
+		fmt.Sprintf("Delete container %s (%s)?", ContainerName(container), container.ID[0:12]),
+		func(options ui.FormResult) {
+			RunContainerAction(app, container, "Deleting", "deleted", func(containerId string) error {
+				return client.RemoveContainer(containerId, options.Bool("force"), options.Bool("volumes"))
+			})
+		})
+	confirm.AddOption("force", "Force removal, killing it if running", false)
+	confirm.AddOption("volumes", "Remove its anonymous volumes", false)
+
+	app.ShowConfirm(confirm)
+}
+
+func ShowPicker(app *ui.Application, title string, options []string, handler ui.PickHandler) {
+	var picker = ui.PickerNew(options, handler)
+
+	var popup = ui.TitledContainerNew(title, picker, true)
+	popup.Border = ui.LineBorder
+	app.ShowPopupWithLayout(popup, ui.CenteredLayout(popup, 60, uint16(len(options)+4)), nil)
 }
 
 func SetupLog() {
@@ -125,52 +314,136 @@ func SetupLog() {
 
 }
 
-func BuildContainersView(app *ui.Application, client *docker.ServiceHandler, width uint16, height uint16) {
+// The value of the selected row, nil when the list is empty
+func SelectedValue(list *ui.List) interface{} {
+	if list.Model.ItemCount() == 0 {
+		return nil
+	}
+	return list.SelectedItem().Value()
+}
+
+/**
+	Binds an action of the containers view to a handler
+	run when a container, not a project, is selected
+**/
+func BindContainerAction(app *ui.Application, containerList *ui.List, name string, handler func(*types.Container)) {
+	app.Keymap().Bind(containerList, name, func(input.KeyInput) {
+		if item, ok := SelectedValue(containerList).(*types.Container); ok {
+			handler(item)
+		}
+	})
+}
+
+/**
+	Binds an action acting on the selected container,
+	or on all the containers of the selected project
+**/
+func BindProjectAction(app *ui.Application, containerList *ui.List, name string,
+	handler func(*types.Container), projectHandler func(*docker.ComposeProject)) {
+
+	app.Keymap().Bind(containerList, name, ProjectActionHandler(containerList, handler, projectHandler))
+}
+
+func ProjectActionHandler(containerList *ui.List,
+	handler func(*types.Container), projectHandler func(*docker.ComposeProject)) ui.KeyHandler {
+
+	return func(input.KeyInput) {
+		switch item := SelectedValue(containerList).(type) {
+		case *types.Container:
+			handler(item)
+		case *docker.ComposeProject:
+			projectHandler(item)
+		}
+	}
+}
+
+func BuildContainersView(app *ui.Application, client *docker.ServiceHandler) (*ui.TitledContainer, *ui.List) {
 	var containerList = ui.ListNew()
+	var model = docker.ContainerListModelNew(client)
 
-	containerList.SetModel(docker.ContainerListModelNew(client))
+	containerList.SetModel(model)
 
-	containerList.AddKeyHandler(input.KeyInputChar('v'), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
+	BindContainerAction(app, containerList, "container.inspect", func(item *types.Container) {
 		ShowContainerInspect(app, client, item.ID)
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('k'), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
-		client.KillContainer(item.ID)
-		containerList.Update()
+	BindContainerAction(app, containerList, "container.kill", func(item *types.Container) {
+		ShowSignalPicker(app, client, item)
+	})
+	BindContainerAction(app, containerList, "container.start", func(item *types.Container) {
+		RunContainerAction(app, item, "Starting", "started", client.StartContainer)
+	})
+	BindProjectAction(app, containerList, "container.stop", func(item *types.Container) {
+		RunContainerAction(app, item, "Stopping", "stopped", client.StopContainer)
+	}, func(project *docker.ComposeProject) {
+		RunProjectAction(app, project, "Stopping", "stopped", client.StopContainer)
+	})
+	BindProjectAction(app, containerList, "container.restart", func(item *types.Container) {
+		RunContainerAction(app, item, "Restarting", "restarted", client.RestartContainer)
+	}, func(project *docker.ComposeProject) {
+		RunProjectAction(app, project, "Restarting", "restarted", client.RestartContainer)
+	})
+	BindContainerAction(app, containerList, "container.pause", func(item *types.Container) {
+		RunContainerAction(app, item, "Pausing", "paused", client.PauseContainer)
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('s'), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
-		ExecShell(item.ID)
+	BindContainerAction(app, containerList, "container.unpause", func(item *types.Container) {
+		RunContainerAction(app, item, "Unpausing", "unpaused", client.UnpauseContainer)
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('d'), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
+	BindContainerAction(app, containerList, "container.shell", func(item *types.Container) {
+		ExecShell(app, item.ID)
+	})
+	app.Keymap().BindDefault(containerList, "container.details", ProjectActionHandler(containerList, func(item *types.Container) {
 		ShowContainerDetails(app, client, item.ID)
+	}, func(project *docker.ComposeProject) {
+		containerList.Model.SetProperty(docker.ContainerListModelToggleCollapsed, project.Name)
+	}))
+	BindContainerAction(app, containerList, "container.bash", func(item *types.Container) {
+		ExecBashShell(app, item.ID)
+	})
+	BindProjectAction(app, containerList, "container.logs", func(item *types.Container) {
+		ShowLogs(app, client, item.ID, docker.LogOptions{})
+	}, func(project *docker.ComposeProject) {
+		ShowProjectLogs(app, client, project, docker.LogOptions{})
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('b'), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
-		ExecBashShell(item.ID)
+	BindProjectAction(app, containerList, "container.log-options", func(item *types.Container) {
+		ShowLogOptions(app, client, ContainerLogSources(item), docker.LogOptions{})
+	}, func(project *docker.ComposeProject) {
+		ShowLogOptions(app, client, ProjectLogSources(project), docker.LogOptions{})
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('l'), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
-		ShowLogs(app, client, item.ID)
+	BindContainerAction(app, containerList, "container.dashboard", func(item *types.Container) {
+		ShowDashboard(app, client, item)
 	})
-	containerList.AddKeyHandler(input.KeyInputKey(keyboard.KeyDelete), func(input.KeyInput) {
-		var item = containerList.SelectedItem().Value().(*types.Container)
-		client.RemoveContainer(item.ID)
-		containerList.Update()
+	BindProjectAction(app, containerList, "container.delete", func(item *types.Container) {
+		ConfirmRemoveContainer(app, client, item)
+	}, func(project *docker.ComposeProject) {
+		ConfirmRemoveProject(app, client, project)
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('a'), func(input.KeyInput) {
+
+	var keymap = app.Keymap()
+
+	keymap.Bind(containerList, "container.only-active", func(input.KeyInput) {
 		containerList.Model.SetProperty(docker.ContainerListModelOnlyActive, nil)
 	})
-	containerList.AddKeyHandler(input.KeyInputChar('h'), func(input.KeyInput) {
-		ShowHelp(app)
+	keymap.Bind(containerList, "container.io", func(input.KeyInput) {
+		containerList.Model.SetProperty(docker.ContainerListModelShowIO, nil)
 	})
+	keymap.Bind(containerList, "container.group", func(input.KeyInput) {
+		containerList.Model.SetProperty(docker.ContainerListModelGrouped, nil)
+	})
+	BindAppActions(app, containerList)
+
+	var titledContainer1 = ui.TitledContainerNew("Containers - "+model.SortDescription(), containerList, false)
 
-	var titledContainer1 = ui.TitledContainerNew("Containers", containerList, false)
-	titledContainer1.SetRect(ui.RectNew(1, 1, width, height-1))
+	keymap.Bind(containerList, "container.sort", func(input.KeyInput) {
+		model.SetProperty(docker.ContainerListModelSortColumn, nil)
+		titledContainer1.SetTitle("Containers - " + model.SortDescription())
+	})
+	keymap.Bind(containerList, "container.sort-order", func(input.KeyInput) {
+		model.SetProperty(docker.ContainerListModelSortOrder, nil)
+		titledContainer1.SetTitle("Containers - " + model.SortDescription())
+	})
 	app.Add(titledContainer1)
 
+	return titledContainer1, containerList
 }
 
 func ShowImageInspect(app *ui.Application, client *docker.ServiceHandler, imageId string) {
@@ -178,7 +451,7 @@ func ShowImageInspect(app *ui.Application, client *docker.ServiceHandler, imageI
 	ShowTextPopup(app, "Image Inspect", result)
 }
 
-func DoRunImage(image types.ImageSummary, command string) {
+func DoRunImage(app *ui.Application, image types.ImageSummary, command string) {
 	var name = ""
 
 	if len(image.RepoTags) > 0 {
@@ -187,66 +460,356 @@ func DoRunImage(image types.ImageSummary, command string) {
 		name = image.ID
 	}
 
-	RunCommand("docker", "run", "-it", "--entrypoint", command, name)
+	DockerCommand(app, "run", "-it", "--entrypoint", command, name)
 }
 
-func RunShell(image types.ImageSummary) {
-	DoRunImage(image, "sh")
+func RunShell(app *ui.Application, image types.ImageSummary) {
+	DoRunImage(app, image, "sh")
 }
 
-func RunBashShell(image types.ImageSummary) {
-	DoRunImage(image, "bash")
+func RunBashShell(app *ui.Application, image types.ImageSummary) {
+	DoRunImage(app, image, "bash")
 }
 
-func BuildImagesView(app *ui.Application, client *docker.ServiceHandler, width uint16, height uint16) {
+func ImageName(image *types.ImageSummary) string {
+	if len(image.RepoTags) > 0 {
+		return image.RepoTags[len(image.RepoTags)-1]
+	}
+	return image.ID[7:19]
+}
+
+func ConfirmRemoveImage(app *ui.Application, client *docker.ServiceHandler, image *types.ImageSummary) {
+	var name = ImageName(image)
+	var confirm = ui.ConfirmNew("Delete image",
+		fmt.Sprintf("Delete image %s (%s)?", name, image.ID[7:19]),
+		func(options ui.FormResult) {
+			err := client.RemoveImage(image.ID, options.Bool("force"))
+			if err != nil {
+				app.ShowError(fmt.Sprintf("Deleting image %s failed: %s", name, err))
+			} else {
+				app.ShowMessage(fmt.Sprintf("Image %s deleted", name))
+			}
+		})
+	confirm.AddOption("force", "Force removal, even if used by stopped containers", false)
+
+	app.ShowConfirm(confirm)
+}
+
+func BuildImagesView(app *ui.Application, client *docker.ServiceHandler) *ui.TitledContainer {
 	var imageList = ui.ListNew()
-	imageList.SetModel(docker.ImagesListModelNew(client))
+	var model = docker.ImagesListModelNew(client)
+	imageList.SetModel(model)
+
+	var keymap = app.Keymap()
 
-	imageList.AddKeyHandler(input.KeyInputChar('v'), func(input.KeyInput) {
-		var item = imageList.SelectedItem().Value().(*types.ImageSummary)
-		ShowImageInspect(app, client, item.ID)
+	keymap.BindDefault(imageList, "image.inspect", func(input.KeyInput) {
+		if item, ok := SelectedValue(imageList).(*types.ImageSummary); ok {
+			ShowImageInspect(app, client, item.ID)
+		}
 	})
-	imageList.AddKeyHandler(input.KeyInputKey(keyboard.KeyDelete), func(input.KeyInput) {
-		var item = imageList.SelectedItem().Value().(*types.ImageSummary)
-		client.RemoveImage(item.ID)
-		imageList.Update()
+	keymap.Bind(imageList, "image.delete", func(input.KeyInput) {
+		if item, ok := SelectedValue(imageList).(*types.ImageSummary); ok {
+			ConfirmRemoveImage(app, client, item)
+		}
 	})
-	imageList.AddKeyHandler(input.KeyInputChar('s'), func(input.KeyInput) {
-		var item = imageList.SelectedItem().Value().(*types.ImageSummary)
-		RunShell(*item)
+	keymap.Bind(imageList, "image.pull", func(input.KeyInput) {
+		ShowPullImage(app, client)
 	})
-	imageList.AddKeyHandler(input.KeyInputChar('b'), func(input.KeyInput) {
-		var item = imageList.SelectedItem().Value().(*types.ImageSummary)
-		RunBashShell(*item)
+	keymap.Bind(imageList, "image.build", func(input.KeyInput) {
+		ShowBuildImage(app, client)
 	})
-	imageList.AddKeyHandler(input.KeyInputChar('h'), func(input.KeyInput) {
-		ShowHelp(app)
+	keymap.Bind(imageList, "image.shell", func(input.KeyInput) {
+		if item, ok := SelectedValue(imageList).(*types.ImageSummary); ok {
+			RunShell(app, *item)
+		}
 	})
+	keymap.Bind(imageList, "image.bash", func(input.KeyInput) {
+		if item, ok := SelectedValue(imageList).(*types.ImageSummary); ok {
+			RunBashShell(app, *item)
+		}
+	})
+	BindAppActions(app, imageList)
+
+	var titledContainer2 = ui.TitledContainerNew("Images - "+model.SortDescription(), imageList, false)
 
-	var titledContainer2 = ui.TitledContainerNew("Images", imageList, false)
-	titledContainer2.SetRect(ui.RectNew(1, height+1, width, height-1))
+	keymap.Bind(imageList, "image.sort", func(input.KeyInput) {
+		model.SetProperty(docker.ImagesListModelSortColumn, nil)
+		titledContainer2.SetTitle("Images - " + model.SortDescription())
+	})
+	keymap.Bind(imageList, "image.sort-order", func(input.KeyInput) {
+		model.SetProperty(docker.ImagesListModelSortOrder, nil)
+		titledContainer2.SetTitle("Images - " + model.SortDescription())
+	})
 	app.Add(titledContainer2)
+
+	return titledContainer2
+}
+
+func ShowVolumeInspect(app *ui.Application, client *docker.ServiceHandler, volumeName string) {
+	result := client.InspectVolume(volumeName)
+	ShowTextPopup(app, "Volume Inspect", result)
+}
+
+func ConfirmRemoveVolume(app *ui.Application, client *docker.ServiceHandler, volume *types.Volume) {
+	var confirm = ui.ConfirmNew("Delete volume",
+		fmt.Sprintf("Delete volume %s and all its data?", volume.Name),
+		func(options ui.FormResult) {
+			err := client.RemoveVolume(volume.Name, options.Bool("force"))
+			if err != nil {
+				app.ShowError(fmt.Sprintf("Deleting volume %s failed: %s", volume.Name, err))
+			} else {
+				app.ShowMessage(fmt.Sprintf("Volume %s deleted", volume.Name))
+			}
+		})
+	confirm.AddOption("force", "Force removal", false)
+
+	app.ShowConfirm(confirm)
+}
+
+func BuildVolumesView(app *ui.Application, client *docker.ServiceHandler) *ui.TitledContainer {
+	var volumeList = ui.ListNew()
+	volumeList.SetModel(docker.VolumesListModelNew(client))
+
+	var keymap = app.Keymap()
+
+	keymap.BindDefault(volumeList, "volume.inspect", func(input.KeyInput) {
+		if item, ok := SelectedValue(volumeList).(*types.Volume); ok {
+			ShowVolumeInspect(app, client, item.Name)
+		}
+	})
+	keymap.Bind(volumeList, "volume.delete", func(input.KeyInput) {
+		if item, ok := SelectedValue(volumeList).(*types.Volume); ok {
+			ConfirmRemoveVolume(app, client, item)
+		}
+	})
+	BindAppActions(app, volumeList)
+
+	var titledContainer = ui.TitledContainerNew("Volumes", volumeList, false)
+	app.Add(titledContainer)
+
+	return titledContainer
+}
+
+func ShowNetworkInspect(app *ui.Application, client *docker.ServiceHandler, networkId string) {
+	result := client.InspectNetwork(networkId)
+	ShowTextPopup(app, "Network Inspect", result)
+}
+
+var NetworkDrivers = []string{"bridge", "overlay", "macvlan", "ipvlan"}
+
+func ValidateSubnet(value interface{}) error {
+	if value != "" {
+		_, _, err := net.ParseCIDR(value.(string))
+		return err
+	}
+	return nil
+}
+
+func ValidateAddress(value interface{}) error {
+	if value != "" && net.ParseIP(value.(string)) == nil {
+		return errors.New("not a valid IP address")
+	}
+	return nil
+}
+
+func ShowCreateNetwork(app *ui.Application, client *docker.ServiceHandler) {
+	var form = ui.FormNew()
+	form.AddTextField("name", "Name", "", nil, ui.RequiredValidator)
+	form.AddSelect("driver", "Driver", NetworkDrivers, NetworkDrivers[0])
+	form.AddTextField("subnet", "Subnet", "", nil, ValidateSubnet)
+	form.AddTextField("gateway", "Gateway", "", nil, ValidateAddress)
+	form.AddCheckbox("internal", "Internal", false)
+
+	app.ShowDialog(ui.DialogNew("Create network", form, 60, func(result ui.FormResult) {
+		var name = result.String("name")
+		err := client.CreateNetwork(docker.NetworkOptions{
+			Name:     name,
+			Driver:   result.String("driver"),
+			Subnet:   result.String("subnet"),
+			Gateway:  result.String("gateway"),
+			Internal: result.Bool("internal"),
+		})
+		if err != nil {
+			app.ShowError(fmt.Sprintf("Creating network %s failed: %s", name, err))
+		} else {
+			app.ShowMessage(fmt.Sprintf("Network %s created", name))
+		}
+	}))
+}
+
+/**
+	Connects or disconnects the container selected in the containers view,
+	or the one selected in the networks view when disconnecting an endpoint
+**/
+func RunNetworkAction(app *ui.Application, client *docker.ServiceHandler, networkList *ui.List, containerList *ui.List, connect bool) {
+	network, ok := SelectedValue(networkList).(*types.NetworkResource)
+	if !ok {
+		return
+	}
+	var item = networkList.SelectedItem().(*docker.NetworkItem)
+	var containerId, containerName string
+
+	if item.Endpoint() != nil && !connect {
+		containerId = item.Endpoint().ContainerID
+		containerName = item.Endpoint().ContainerName
+	} else {
+		var container, ok = SelectedValue(containerList).(*types.Container)
+		if !ok {
+			app.ShowError("Select a container in the containers view first")
+			return
+		}
+		containerId = container.ID
+		containerName = ContainerName(container)
+	}
+
+	var err error
+	var action = "Connecting"
+	var done = "connected to"
+
+	if connect {
+		err = client.ConnectNetwork(network.ID, containerId)
+	} else {
+		action = "Disconnecting"
+		done = "disconnected from"
+		err = client.DisconnectNetwork(network.ID, containerId)
+	}
+
+	if err != nil {
+		app.ShowError(fmt.Sprintf("%s container %s failed: %s", action, containerName, err))
+	} else {
+		app.ShowMessage(fmt.Sprintf("Container %s %s network %s", containerName, done, network.Name))
+	}
+}
+
+func BuildNetworksView(app *ui.Application, client *docker.ServiceHandler, containerList *ui.List) *ui.TitledContainer {
+	var networkList = ui.ListNew()
+	networkList.SetModel(docker.NetworksListModelNew(client))
+
+	var keymap = app.Keymap()
+
+	keymap.BindDefault(networkList, "network.expand", func(input.KeyInput) {
+		item, ok := SelectedValue(networkList).(*types.NetworkResource)
+		if !ok {
+			return
+		}
+		networkList.Model.SetProperty(docker.NetworksListModelToggleExpanded, item.ID)
+	})
+	keymap.Bind(networkList, "network.inspect", func(input.KeyInput) {
+		item, ok := SelectedValue(networkList).(*types.NetworkResource)
+		if !ok {
+			return
+		}
+		ShowNetworkInspect(app, client, item.ID)
+	})
+	keymap.Bind(networkList, "network.create", func(input.KeyInput) {
+		ShowCreateNetwork(app, client)
+	})
+	keymap.Bind(networkList, "network.delete", func(input.KeyInput) {
+		item, ok := SelectedValue(networkList).(*types.NetworkResource)
+		if !ok {
+			return
+		}
+		app.ShowConfirm(ui.ConfirmNew("Delete network",
+			fmt.Sprintf("Delete network %s (%s)?", item.Name, item.ID[0:12]),
+			func(ui.FormResult) {
+				err := client.RemoveNetwork(item.ID)
+				if err != nil {
+					app.ShowError(fmt.Sprintf("Deleting network %s failed: %s", item.Name, err))
+				} else {
+					app.ShowMessage(fmt.Sprintf("Network %s deleted", item.Name))
+				}
+			}))
+	})
+	keymap.Bind(networkList, "network.connect", func(input.KeyInput) {
+		RunNetworkAction(app, client, networkList, containerList, true)
+	})
+	keymap.Bind(networkList, "network.disconnect", func(input.KeyInput) {
+		RunNetworkAction(app, client, networkList, containerList, false)
+	})
+	BindAppActions(app, networkList)
+
+	var titledContainer = ui.TitledContainerNew("Networks", networkList, false)
+	app.Add(titledContainer)
+
+	return titledContainer
+}
+
+var configPath = flag.String("config", config.DefaultPath(), "config file, with key bindings and theme")
+var stopTimeout = flag.Duration("stop-timeout", docker.DefaultStopTimeout, "time given to containers to stop before killing them")
+
+/**
+	Sets the theme and color mode, flags take precedence over the config file.
+	Errors start with the flag or the config file the wrong value comes from.
+**/
+func SetupTheme(cfg *config.Config, configPath string, themeName string, colors string) error {
+	var themeSource = "option -theme"
+	if themeName == "" {
+		themeName = cfg.Theme
+		themeSource = "config file " + configPath
+	}
+	if themeName != "" {
+		theme, err := ui.FindTheme(themeName)
+		if err != nil {
+			return fmt.Errorf("%s: theme %s: %s", themeSource, themeName, err)
+		}
+		ui.SetTheme(theme)
+	}
+
+	var colorsSource = "option -colors"
+	if colors == "" {
+		colors = cfg.Colors
+		colorsSource = "config file " + configPath
+	}
+	if colors == "" {
+		ui.CurrentColorMode = ui.DetectColorMode()
+	} else {
+		mode, err := ui.ParseColorMode(colors)
+		if err != nil {
+			return fmt.Errorf("%s: %s", colorsSource, err)
+		}
+		ui.CurrentColorMode = mode
+	}
+	return nil
 }
 
 func main() {
 
-	SetupLog()
+	var themeName = flag.String("theme", "", "theme, one of "+strings.Join(ui.BuiltinThemeNames(), ", ")+" or the path of a theme file")
+	var colors = flag.String("colors", "", "colors supported by the terminal: 16, 256 or truecolor, guessed when not given")
+	var contextName = flag.String("context", docker.CurrentContextName(), "docker context to connect to")
+	flag.Parse()
 
-	client, err1 := client.NewClientWithOpts(client.FromEnv)
+	var keymap = ui.KeymapNew()
+	DefineActions(keymap)
 
-	service := docker.ServiceHandlerNew(client)
-	service.RemoveContainer("")
+	cfg, err := config.Load(*configPath)
+	if err == nil {
+		err = keymap.RemapNames(cfg.KeyBindings())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config file %s: %s\n", *configPath, err)
+		os.Exit(1)
+	}
 
-	if err1 != nil {
-		panic(err1)
+	if err = SetupTheme(cfg, *configPath, *themeName, *colors); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in %s\n", err)
+		os.Exit(1)
+	}
+
+	dockerContext, err := docker.FindContext(*contextName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding docker context %s: %s\n", *contextName, err)
+		os.Exit(1)
 	}
-	maxWidth, maxHeight := ui.ScreenSize()
-	areaHeight := maxHeight / 2
+
+	SetupLog()
 
 	var app = ui.ApplicationNew()
+	app.SetKeymap(keymap)
 
-	BuildContainersView(app, service, maxWidth, areaHeight)
-	BuildImagesView(app, service, maxWidth, areaHeight)
+	if err = Connect(app, dockerContext); err != nil {
+		panic(err)
+	}
 
 	app.Loop()
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/clidockermgr/docker"
+	"github.com/clidockermgr/ui"
+	"github.com/clidockermgr/util"
+)
+
+const ProgressBarWidth = 30
+
+/**
+	Keeps the latest progress of each layer being pulled,
+	in the order layers were first reported.
+**/
+type PullProgress struct {
+	mutex    sync.Mutex
+	layers   []string
+	progress map[string]docker.ProgressMessage
+	status   []string
+	textView *ui.TextView
+}
+
+func PullProgressNew(textView *ui.TextView) *PullProgress {
+	return &PullProgress{progress: make(map[string]docker.ProgressMessage), textView: textView}
+}
+
+func (p *PullProgress) Update(message docker.ProgressMessage) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if message.Error != "" {
+		return
+	}
+
+	if message.ID == "" {
+		p.status = append(p.status, message.Status)
+	} else {
+		if _, ok := p.progress[message.ID]; !ok {
+			p.layers = append(p.layers, message.ID)
+		}
+		p.progress[message.ID] = message
+	}
+
+	p.textView.SetText(p.Render())
+}
+
+func (p *PullProgress) Render() string {
+	var lines []string
+
+	for _, id := range p.layers {
+		var message = p.progress[id]
+		var line = fmt.Sprintf("%-12s: %-20s", id, message.Status)
+
+		if message.Progress.Total > 0 {
+			line += fmt.Sprintf(" %s %s / %s",
+				util.FormatProgressBar(message.Progress.Current, message.Progress.Total, ProgressBarWidth),
+				util.FormatMemory(uint64(message.Progress.Current)),
+				util.FormatMemory(uint64(message.Progress.Total)))
+		}
+		lines = append(lines, line)
+	}
+
+	if len(p.status) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, p.status...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var PullHistory = ui.InputHistoryNew()
+
+/**
+	Pulls an image showing its progress in a popup, the pull
+	goes on in background if the popup gets closed.
+**/
+func ShowPullImage(app *ui.Application, client *docker.ServiceHandler) {
+	var form = ui.FormNew()
+	form.AddTextField("reference", "Image", "", PullHistory, ui.RequiredValidator)
+
+	app.ShowDialog(ui.DialogNew("Image to pull, like alpine:latest or localhost:5000/image", form, 70, func(result ui.FormResult) {
+		var reference = result.String("reference")
+		var textView = ui.TextViewNew("")
+		var popup = MakeTextPopup("Pulling "+reference, textView)
+		var progress = PullProgressNew(textView)
+
+		ShowTextContainer(app, popup, nil)
+		app.ShowMessage("Pulling " + reference + "...")
+
+		go func() {
+			err := client.PullImage(reference, progress.Update)
+
+			if err != nil {
+				textView.AppendStyled(err.Error(), ui.ErrorStyle)
+				popup.SetTitle("Pulling " + reference + " - failed")
+				app.ShowError(fmt.Sprintf("Pulling %s failed: %s", reference, err))
+			} else {
+				popup.SetTitle("Pulling " + reference + " - done")
+				app.ShowMessage("Pulled " + reference)
+			}
+		}()
+	}))
+}
@@ -54,11 +54,25 @@ func Background(color uint16) {
 	fmt.Printf("\u001b[48;5;%dm", color)
 }
 
+func ErrorStyle() {
+	CurrentTheme.Error.Apply()
+}
+
+func StatusStyle() {
+	CurrentTheme.Status.Apply()
+}
+
+func HeaderStyle() {
+	CurrentTheme.Header.Apply()
+}
+
+// Counts characters rather than bytes, so that block and box characters fit
 func WriteFill(text string, length uint16) {
-	if len(text) > int(length) {
-		fmt.Print(text[0:length])
+	var runes = []rune(text)
+	if len(runes) > int(length) {
+		fmt.Print(string(runes[0:length]))
 	} else {
-		fmt.Printf("%s%s", text, strings.Repeat(" ", int(length)-len(text)))
+		fmt.Printf("%s%s", text, strings.Repeat(" ", int(length)-len(runes)))
 	}
 }
 
@@ -85,6 +99,10 @@ func UnderlineOn() {
 	fmt.Print("\u001b[4m")
 }
 
+func ReverseOn() {
+	fmt.Print("\u001b[7m")
+}
+
 func Reset() {
 	fmt.Print("\u001b[0m")
 }
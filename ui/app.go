@@ -2,10 +2,12 @@ package ui
 
 import (
 	"container/list"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/clidockermgr/input"
-	"github.com/eiannone/keyboard"
 )
 
 type Application struct {
@@ -14,6 +16,13 @@ type Application struct {
 	inputHandler   *input.InputHandler
 	running        bool
 	currentPopup   View
+	popupClosed    CloseHandler
+	popupLayout    LayoutHandler
+	statusBar      *Label
+	layout         LayoutHandler
+	rootLayout     Layout
+	keymap         *Keymap
+	resized        chan os.Signal
 }
 
 func ApplicationNew() *Application {
@@ -23,6 +32,8 @@ func ApplicationNew() *Application {
 		currentElement: nil,
 		currentPopup:   nil,
 		inputHandler:   input.InputHandlerNew(),
+		resized:        make(chan os.Signal, 1),
+		keymap:         KeymapNew(),
 	}
 }
 
@@ -35,23 +46,149 @@ func (a *Application) Add(view View) {
 	}
 }
 
+/**
+	Removes all the views and closes the popup, so that
+	they can be built again
+**/
+func (a *Application) Clear() {
+	a.ClosePopup()
+	a.children.Init()
+	a.currentElement = nil
+	a.rootLayout = nil
+	a.layout = nil
+}
+
+func (a *Application) SetKeymap(keymap *Keymap) {
+	a.keymap = keymap
+}
+
+func (a *Application) Keymap() *Keymap {
+	return a.keymap
+}
+
+/**
+	Sets the label where messages and errors are reported
+**/
+func (a *Application) SetStatusBar(label *Label) {
+	a.statusBar = label
+	label.Style = StatusStyle
+}
+
+func (a *Application) ShowMessage(message string) {
+	if a.statusBar != nil {
+		a.statusBar.SetText(message, StatusStyle)
+	}
+}
+
+func (a *Application) ShowError(message string) {
+	if a.statusBar != nil {
+		a.statusBar.SetText(message, ErrorStyle)
+	}
+}
+
+/**
+	Sets the function which places the views on the screen, it is
+	called right away and again every time the terminal is resized.
+**/
+func (a *Application) SetLayout(layout LayoutHandler) {
+	a.layout = layout
+	a.layout(ScreenSize())
+}
+
+/**
+	Sets the layout placing the views on the whole screen, the
+	focused view can then be grown or shrunk with + and -,
+	and = goes back to the initial sizes.
+**/
+func (a *Application) SetRootLayout(layout Layout) {
+	a.rootLayout = layout
+	a.SetLayout(func(width, height uint16) {
+		layout.SetRect(RectNew(1, 1, width, height))
+	})
+}
+
+func (a *Application) ResizeCurrent(delta int) {
+	if a.rootLayout != nil && a.rootLayout.Resize(a.CurrentView(), delta) {
+		a.Resize()
+	}
+}
+
+func (a *Application) ResetSizes() {
+	if a.rootLayout != nil {
+		a.rootLayout.ResetSizes()
+		a.Resize()
+	}
+}
+
+func (a *Application) HandleLayoutKeys(input input.KeyInput) bool {
+	if a.currentPopup != nil {
+		return false
+	}
+	switch {
+	case a.keymap.Matches("app.grow", input):
+		a.ResizeCurrent(1)
+	case a.keymap.Matches("app.shrink", input):
+		a.ResizeCurrent(-1)
+	case a.keymap.Matches("app.reset-sizes", input):
+		a.ResetSizes()
+	default:
+		return false
+	}
+	return true
+}
+
 func (a *Application) ShowPopup(view View) {
+	a.ShowPopupWithCloseHandler(view, nil)
+}
+
+/**
+	Shows a popup, the handler is called once the popup gets closed.
+	The popup keeps its rect when the terminal is resized.
+**/
+func (a *Application) ShowPopupWithCloseHandler(view View, handler CloseHandler) {
+	a.ShowPopupWithLayout(view, nil, handler)
+}
+
+/**
+	Shows a popup placed by the given layout, which is called
+	right away and again every time the terminal is resized.
+**/
+func (a *Application) ShowPopupWithLayout(view View, layout LayoutHandler, handler CloseHandler) {
+	a.ClosePopup()
+	if layout != nil {
+		layout(ScreenSize())
+	}
 	a.currentPopup = view
+	a.popupClosed = handler
+	a.popupLayout = layout
 }
 
 func (a *Application) ClosePopup() {
+	if a.popupClosed != nil {
+		a.popupClosed()
+		a.popupClosed = nil
+	}
 	a.currentPopup = nil
+	a.popupLayout = nil
 	a.MarkAllForRedraw()
 }
 
 func (a *Application) CycleCurrent() {
+	var next = a.currentElement.Next()
+	if next == nil {
+		next = a.children.Front()
+	}
+	a.SetCurrent(next)
+}
+
+func (a *Application) SetCurrent(element *list.Element) {
+	if a.currentElement == element {
+		return
+	}
 	if a.currentElement != nil {
 		a.currentElement.Value.(View).SetFocused(false)
 	}
-	a.currentElement = a.currentElement.Next()
-	if a.currentElement == nil {
-		a.currentElement = a.children.Front()
-	}
+	a.currentElement = element
 	a.currentElement.Value.(View).SetFocused(true)
 }
 
@@ -63,39 +200,81 @@ func (a *Application) CurrentView() View {
 }
 
 func (a *Application) CheckInput() bool {
-	input, available := a.inputHandler.GetKeyInput()
+	event, available := a.inputHandler.GetEvent()
 
 	if available {
-		key := input.GetKey()
+		switch event := event.(type) {
+		case input.KeyInput:
+			a.HandleKey(event)
+		case input.MouseInput:
+			a.HandleMouse(event)
+		}
+		return true
+	}
+	return false
+}
 
-		switch key {
-		case keyboard.KeyTab:
+func (a *Application) HandleKey(input input.KeyInput) {
+	var target = a.currentPopup
+	if target == nil {
+		target = a.CurrentView()
+	}
+
+	if target != nil && CapturesInput(target) {
+		target.HandleInput(input)
+		return
+	}
+
+	switch {
+	case a.keymap.Matches("app.next", input):
+		if a.currentPopup != nil {
+			a.currentPopup.HandleInput(input)
+		} else {
 			a.CycleCurrent()
-		case keyboard.KeyEsc:
-			if a.currentPopup != nil {
-				a.ClosePopup()
-			} else {
-				a.running = false
-			}
-		default:
-			if a.currentPopup != nil {
-				a.currentPopup.HandleInput(input)
-			} else {
-				var currentView = a.CurrentView()
-				if currentView != nil {
-					currentView.HandleInput(input)
-				}
+		}
+	case a.keymap.Matches("app.close", input):
+		if a.currentPopup != nil {
+			a.ClosePopup()
+		} else {
+			a.running = false
+		}
+	default:
+		if !a.HandleLayoutKeys(input) && target != nil {
+			target.HandleInput(input)
+		}
+	}
+}
+
+/**
+	Sends mouse events to the view under the pointer, while a
+	popup is open only the popup gets them. Clicking a view
+	gives it the focus.
+**/
+func (a *Application) HandleMouse(mouse input.MouseInput) {
+	if a.currentPopup != nil {
+		HandleMouse(a.currentPopup, mouse)
+		return
+	}
+
+	for v := a.children.Front(); v != nil; v = v.Next() {
+		var view = v.Value.(View)
+		if view.Contains(mouse.X, mouse.Y) {
+			if mouse.IsClick() {
+				a.SetCurrent(v)
 			}
+			HandleMouse(view, mouse)
+			return
 		}
-		return true
 	}
-	return false
 }
 
 func (a *Application) MarkAllForRedraw() {
 	for v := a.children.Front(); v != nil; v = v.Next() {
 		v.Value.(View).RequestRedraw()
 	}
+	if a.statusBar != nil {
+		a.statusBar.RequestRedraw()
+	}
 }
 
 func (a *Application) DrawAll() {
@@ -111,14 +290,76 @@ func (a *Application) DrawAll() {
 		}
 	}
 
+	if a.statusBar != nil && a.statusBar.CheckRedrawFlag() {
+		a.statusBar.Draw()
+	}
+
+}
+
+/**
+	Lays out everything again for the current screen size and
+	redraws the whole screen, including what lies behind a popup.
+**/
+func (a *Application) Resize() {
+	width, height := ScreenSize()
+
+	if a.layout != nil {
+		a.layout(width, height)
+	}
+	if a.popupLayout != nil {
+		a.popupLayout(width, height)
+	}
+
+	ClearScreen()
+	a.MarkAllForRedraw()
+
+	if a.currentPopup != nil {
+		for v := a.children.Front(); v != nil; v = v.Next() {
+			var view = v.Value.(View)
+			view.CheckRedrawFlag()
+			view.Draw()
+		}
+		a.currentPopup.RequestRedraw()
+	}
+}
+
+func (a *Application) CheckResize() bool {
+	select {
+	case <-a.resized:
+		a.Resize()
+		return true
+	default:
+		return false
+	}
+}
+
+/**
+	Gives the terminal back to run another program, input
+	is read again and the screen redrawn on Resume
+**/
+func (a *Application) Suspend() {
+	a.inputHandler.Suspend()
+	CursorOn()
+	ClearScreen()
+}
+
+func (a *Application) Resume() {
+	a.inputHandler.Resume()
+	CursorOff()
+	ClearScreen()
+	a.MarkAllForRedraw()
 }
 
 func (a *Application) Loop() {
+	signal.Notify(a.resized, syscall.SIGWINCH)
+	defer signal.Stop(a.resized)
+
 	ClearScreen()
 	CursorOff()
 	a.DrawAll()
 	for a.running {
-		hasEvents := a.CheckInput()
+		hasEvents := a.CheckResize()
+		hasEvents = a.CheckInput() || hasEvents
 
 		a.DrawAll()
 
@@ -1,6 +1,9 @@
 package ui
 
-import "github.com/clidockermgr/input"
+import (
+	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/util"
+)
 
 type Rect struct {
 	x uint16
@@ -18,7 +21,31 @@ type Insets struct {
 
 type KeyHandler func(input.KeyInput)
 type RedrawListener func(view interface{})
+type CloseHandler func()
+
+// Lays out views for the given screen size
+type LayoutHandler func(width, height uint16)
 
 func RectNew(x, y, w, h uint16) Rect {
 	return Rect{x, y, w, h}
 }
+
+// A rect of the given size centered on the screen
+func CenteredRect(w, h uint16) Rect {
+	maxWidth, maxHeight := ScreenSize()
+	return CenteredRectIn(w, h, maxWidth, maxHeight)
+}
+
+// A rect of the given size centered on a screen of the given size
+func CenteredRectIn(w, h, maxWidth, maxHeight uint16) Rect {
+	w = uint16(util.Min(int(w), int(maxWidth)))
+	h = uint16(util.Min(int(h), int(maxHeight)))
+	return Rect{(maxWidth-w)/2 + 1, (maxHeight-h)/2 + 1, w, h}
+}
+
+// A layout which keeps a view of the given size centered on the screen
+func CenteredLayout(view View, w, h uint16) LayoutHandler {
+	return func(maxWidth, maxHeight uint16) {
+		view.SetRect(CenteredRectIn(w, h, maxWidth, maxHeight))
+	}
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"github.com/clidockermgr/input"
+	"github.com/eiannone/keyboard"
+)
+
+/**
+	A checkbox, toggled with space
+**/
+type Checkbox struct {
+	ViewImpl
+	label   string
+	checked bool
+}
+
+func CheckboxNew(label string, checked bool) *Checkbox {
+	var checkbox = Checkbox{label: label, checked: checked}
+	checkbox.Init()
+	return &checkbox
+}
+
+func (c *Checkbox) Checked() bool {
+	return c.checked
+}
+
+func (c *Checkbox) SetChecked(checked bool) {
+	c.checked = checked
+	c.RequestRedraw()
+}
+
+func (c *Checkbox) FormValue() interface{} {
+	return c.checked
+}
+
+func (c *Checkbox) HandleInput(key input.KeyInput) {
+	if key.GetKey() == keyboard.KeySpace || key.GetChar() == 'x' {
+		c.SetChecked(!c.checked)
+	} else {
+		c.ViewImpl.HandleInput(key)
+	}
+}
+
+func (c *Checkbox) Draw() {
+	GotoXY(c.rect.x, c.rect.y)
+
+	var mark = "[ ] "
+	if c.checked {
+		mark = "[x] "
+	}
+	if c.focused {
+		CurrentTheme.Control.Apply()
+		WriteFill(mark, 4)
+		Reset()
+	} else {
+		WriteFill(mark, 4)
+	}
+	if c.rect.w > 4 {
+		WriteFill(c.label, c.rect.w-4)
+	}
+}
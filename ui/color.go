@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type ColorMode int
+
+const (
+	Colors16 ColorMode = iota
+	Colors256
+	ColorsTrue
+)
+
+// Colors used when writing styles, see DetectColorMode
+var CurrentColorMode = Colors256
+
+/**
+	Guesses what the terminal supports from COLORTERM and TERM
+**/
+func DetectColorMode() ColorMode {
+	var colorTerm = os.Getenv("COLORTERM")
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorsTrue
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Colors256
+	}
+	return Colors16
+}
+
+func ParseColorMode(text string) (ColorMode, error) {
+	switch strings.ToLower(text) {
+	case "16":
+		return Colors16, nil
+	case "256":
+		return Colors256, nil
+	case "truecolor", "24bit":
+		return ColorsTrue, nil
+	}
+	return Colors16, fmt.Errorf("unknown color mode '%s', use 16, 256 or truecolor", text)
+}
+
+type colorKind int
+
+const (
+	colorDefault colorKind = iota
+	colorIndexed
+	colorRGB
+)
+
+/**
+	A terminal color, either the default one, one of the 256
+	indexed colors, or an RGB color
+**/
+type Color struct {
+	kind    colorKind
+	index   uint8
+	r, g, b uint8
+}
+
+var DefaultColor = Color{}
+
+func IndexedColor(index uint8) Color {
+	return Color{kind: colorIndexed, index: index}
+}
+
+func RGBColor(r, g, b uint8) Color {
+	return Color{kind: colorRGB, r: r, g: g, b: b}
+}
+
+var colorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow",
+	"bright-blue", "bright-magenta", "bright-cyan", "bright-white",
+}
+
+// Usual RGB values of the 16 basic colors, to find the closest one
+var basicColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+/**
+	Parses a color as written in theme files: "default", a name like
+	"red" or "bright-blue", an index from 0 to 255, or "#rrggbb"
+**/
+func ParseColor(text string) (Color, error) {
+	var name = strings.ToLower(strings.TrimSpace(text))
+
+	if name == "" || name == "default" {
+		return DefaultColor, nil
+	}
+	for i := range colorNames {
+		if colorNames[i] == name {
+			return IndexedColor(uint8(i)), nil
+		}
+	}
+	if strings.HasPrefix(name, "#") && len(name) == 7 {
+		value, err := strconv.ParseUint(name[1:], 16, 32)
+		if err == nil {
+			return RGBColor(uint8(value>>16), uint8(value>>8), uint8(value)), nil
+		}
+	}
+	if index, err := strconv.ParseUint(name, 10, 8); err == nil {
+		return IndexedColor(uint8(index)), nil
+	}
+	return DefaultColor, fmt.Errorf("unknown color '%s'", text)
+}
+
+func (c Color) IsDefault() bool {
+	return c.kind == colorDefault
+}
+
+func (c Color) rgb() (int, int, int) {
+	if c.kind == colorRGB {
+		return int(c.r), int(c.g), int(c.b)
+	}
+	if c.index < 16 {
+		var basic = basicColors[c.index]
+		return int(basic[0]), int(basic[1]), int(basic[2])
+	}
+	if c.index >= 232 {
+		var level = 8 + 10*int(c.index-232)
+		return level, level, level
+	}
+	var cube = int(c.index) - 16
+	var level = func(value int) int {
+		if value == 0 {
+			return 0
+		}
+		return 55 + value*40
+	}
+	return level(cube / 36), level(cube / 6 % 6), level(cube % 6)
+}
+
+// The closest of the 256 indexed colors
+func (c Color) to256() uint8 {
+	if c.kind == colorIndexed {
+		return c.index
+	}
+	var step = func(value uint8) int {
+		if value < 48 {
+			return 0
+		}
+		if value < 115 {
+			return 1
+		}
+		return (int(value) - 35) / 40
+	}
+	return uint8(16 + 36*step(c.r) + 6*step(c.g) + step(c.b))
+}
+
+// The closest of the 16 basic colors
+func (c Color) to16() uint8 {
+	if c.kind == colorIndexed && c.index < 16 {
+		return c.index
+	}
+	r, g, b := c.rgb()
+	var best = 0
+	var bestDistance = -1
+	for i := range basicColors {
+		var dr = r - int(basicColors[i][0])
+		var dg = g - int(basicColors[i][1])
+		var db = b - int(basicColors[i][2])
+		var distance = dr*dr + dg*dg + db*db
+		if bestDistance < 0 || distance < bestDistance {
+			best = i
+			bestDistance = distance
+		}
+	}
+	return uint8(best)
+}
+
+/**
+	The SGR parameters selecting the color, for the given mode
+**/
+func (c Color) code(background bool, mode ColorMode) string {
+	if c.kind == colorDefault {
+		return ""
+	}
+
+	var base = 38
+	if background {
+		base = 48
+	}
+
+	switch {
+	case mode == ColorsTrue && c.kind == colorRGB:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, c.r, c.g, c.b)
+	case mode == Colors16:
+		var index = int(c.to16())
+		var offset = 30
+		if background {
+			offset = 40
+		}
+		if index >= 8 {
+			return strconv.Itoa(offset + 60 + index - 8)
+		}
+		return strconv.Itoa(offset + index)
+	default:
+		return fmt.Sprintf("%d;5;%d", base, c.to256())
+	}
+}
+
+func (c Color) String() string {
+	switch c.kind {
+	case colorIndexed:
+		if int(c.index) < len(colorNames) {
+			return colorNames[c.index]
+		}
+		return strconv.Itoa(int(c.index))
+	case colorRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+	}
+	return "default"
+}
+
+func (c *Color) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return err
+	}
+	color, err := ParseColor(text)
+	if err != nil {
+		return err
+	}
+	*c = color
+	return nil
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/util"
+	"github.com/eiannone/keyboard"
+)
+
+type ConfirmHandler func(options FormResult)
+
+type confirmOption struct {
+	name     string
+	checkbox *Checkbox
+}
+
+/**
+	A yes/no question. Only y, or enter on the yes button, confirm;
+	the no button is focused by default. Options shown as checkboxes
+	are passed to the handler.
+**/
+type Confirm struct {
+	ViewImpl
+	title   string
+	message []string
+	options []confirmOption
+	current int
+	yes     bool
+	handler ConfirmHandler
+	app     *Application
+}
+
+func ConfirmNew(title string, message string, handler ConfirmHandler) *Confirm {
+	var confirm = Confirm{title: title, message: strings.Split(message, "\n"), handler: handler}
+	confirm.Init()
+	return &confirm
+}
+
+func (c *Confirm) AddOption(name string, label string, checked bool) {
+	var checkbox = CheckboxNew(label, checked)
+	checkbox.SetFocused(len(c.options) == 0)
+	c.options = append(c.options, confirmOption{name: name, checkbox: checkbox})
+}
+
+func (c *Confirm) Width() uint16 {
+	var width = util.Max(40, len(c.title)+2)
+	for i := range c.message {
+		if len(c.message[i])+2 > width {
+			width = len(c.message[i]) + 2
+		}
+	}
+	for i := range c.options {
+		if len(c.options[i].checkbox.label)+6 > width {
+			width = len(c.options[i].checkbox.label) + 6
+		}
+	}
+	return uint16(width)
+}
+
+// Rows needed for the message, the options and the buttons
+func (c *Confirm) Height() uint16 {
+	var height = len(c.message) + 2
+	if len(c.options) > 0 {
+		height += len(c.options) + 1
+	}
+	return uint16(height)
+}
+
+func (c *Confirm) SetRect(rect Rect) {
+	c.ViewImpl.SetRect(rect)
+
+	var y = rect.y + uint16(len(c.message)) + 1
+	for i := range c.options {
+		c.options[i].checkbox.SetRect(Rect{rect.x, y + uint16(i), rect.w, 1})
+	}
+}
+
+func (c *Confirm) Close() {
+	if c.app != nil {
+		c.app.ClosePopup()
+	}
+}
+
+func (c *Confirm) Accept() {
+	var result = make(FormResult)
+	for i := range c.options {
+		result[c.options[i].name] = c.options[i].checkbox.Checked()
+	}
+	c.Close()
+	c.handler(result)
+}
+
+func (c *Confirm) HandleInput(key input.KeyInput) {
+	switch key.GetKey() {
+	case keyboard.KeyArrowLeft, keyboard.KeyArrowRight:
+		c.yes = !c.yes
+	case keyboard.KeyEnter:
+		if c.yes {
+			c.Accept()
+		} else {
+			c.Close()
+		}
+		return
+	case keyboard.KeyTab:
+		if len(c.options) > 0 {
+			c.options[c.current].checkbox.SetFocused(false)
+			c.current = (c.current + 1) % len(c.options)
+			c.options[c.current].checkbox.SetFocused(true)
+		}
+	case keyboard.KeySpace:
+		if len(c.options) > 0 {
+			c.options[c.current].checkbox.HandleInput(key)
+		}
+	default:
+		switch key.GetChar() {
+		case 'y', 'Y':
+			c.Accept()
+			return
+		case 'n', 'N':
+			c.Close()
+			return
+		}
+	}
+	c.RequestRedraw()
+}
+
+func (c *Confirm) Draw() {
+	var y = c.rect.y
+
+	for i := range c.message {
+		GotoXY(c.rect.x, y)
+		WriteFill(c.message[i], c.rect.w)
+		y++
+	}
+	GotoXY(c.rect.x, y)
+	WriteFill("", c.rect.w)
+	y++
+
+	if len(c.options) > 0 {
+		for i := range c.options {
+			c.options[i].checkbox.Draw()
+			y++
+		}
+		GotoXY(c.rect.x, y)
+		WriteFill("", c.rect.w)
+		y++
+	}
+
+	GotoXY(c.rect.x, y)
+	c.DrawButton("[ Yes ]", c.yes)
+	WriteFill("", 2)
+	c.DrawButton("[ No ]", !c.yes)
+	WriteFill("", c.rect.w-15)
+}
+
+func (c *Confirm) DrawButton(label string, focused bool) {
+	if focused {
+		CurrentTheme.Control.Apply()
+	}
+	WriteFill(label, uint16(len(label)))
+	Reset()
+}
+
+/**
+	Shows a confirmation as a popup, centered on the screen
+**/
+func (a *Application) ShowConfirm(confirm *Confirm) {
+	confirm.app = a
+
+	var container = TitledContainerNew(confirm.title, confirm, true)
+	container.Border = LineBorder
+	a.ShowPopupWithLayout(container, CenteredLayout(container, confirm.Width()+2, confirm.Height()+2), nil)
+}
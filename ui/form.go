@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/util"
+	"github.com/eiannone/keyboard"
+)
+
+/**
+	Implemented by views which can be used as form fields
+**/
+type FormInput interface {
+	View
+	FormValue() interface{}
+}
+
+type FormValidator func(value interface{}) error
+
+type FormResult map[string]interface{}
+
+func RequiredValidator(value interface{}) error {
+	if value == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
+
+func (r FormResult) String(name string) string {
+	value, _ := r[name].(string)
+	return value
+}
+
+func (r FormResult) Bool(name string) bool {
+	value, _ := r[name].(bool)
+	return value
+}
+
+type FormField struct {
+	name      string
+	label     string
+	input     FormInput
+	validator FormValidator
+}
+
+/**
+	A set of labelled fields, one per line. Tab moves
+	the focus to the next field.
+**/
+type Form struct {
+	ViewImpl
+	fields     []FormField
+	current    int
+	labelWidth uint16
+	err        string
+}
+
+func FormNew() *Form {
+	var form = Form{}
+	form.Init()
+	return &form
+}
+
+func (f *Form) AddField(name string, label string, input FormInput, validator FormValidator) {
+	f.fields = append(f.fields, FormField{name: name, label: label, input: input, validator: validator})
+	if uint16(len(label)+2) > f.labelWidth {
+		f.labelWidth = uint16(len(label) + 2)
+	}
+	input.SetFocused(len(f.fields)-1 == f.current)
+}
+
+func (f *Form) AddTextField(name string, label string, text string, history *InputHistory, validator FormValidator) *TextInput {
+	var textInput = TextInputNew(text)
+	if history != nil {
+		textInput.SetHistory(history)
+	}
+	f.AddField(name, label, textInput, validator)
+	return textInput
+}
+
+func (f *Form) AddCheckbox(name string, label string, checked bool) *Checkbox {
+	var checkbox = CheckboxNew("", checked)
+	f.AddField(name, label, checkbox, nil)
+	return checkbox
+}
+
+func (f *Form) AddSelect(name string, label string, options []string, selected string) *Select {
+	var selectView = SelectNew(options, selected)
+	f.AddField(name, label, selectView, nil)
+	return selectView
+}
+
+// Rows needed to show all fields plus the error line
+func (f *Form) Height() uint16 {
+	return uint16(len(f.fields) + 2)
+}
+
+func (f *Form) SetRect(rect Rect) {
+	f.ViewImpl.SetRect(rect)
+
+	var width = uint16(util.Max(0, int(rect.w)-int(f.labelWidth)))
+	for i := range f.fields {
+		f.fields[i].input.SetRect(Rect{rect.x + f.labelWidth, rect.y + uint16(i), width, 1})
+	}
+}
+
+func (f *Form) Focus(index int) {
+	if len(f.fields) == 0 {
+		return
+	}
+	f.fields[f.current].input.SetFocused(false)
+	f.current = index % len(f.fields)
+	f.fields[f.current].input.SetFocused(true)
+	f.RequestRedraw()
+}
+
+func (f *Form) SetError(err string) {
+	f.err = err
+	f.RequestRedraw()
+}
+
+/**
+	Checks every field, on failure the error is shown and
+	the offending field gets the focus
+**/
+func (f *Form) Validate() bool {
+	for i := range f.fields {
+		if f.fields[i].validator == nil {
+			continue
+		}
+		err := f.fields[i].validator(f.fields[i].input.FormValue())
+		if err != nil {
+			f.SetError(fmt.Sprintf("%s: %s", f.fields[i].label, err))
+			f.Focus(i)
+			return false
+		}
+	}
+	f.SetError("")
+	return true
+}
+
+func (f *Form) Result() FormResult {
+	var result = make(FormResult)
+	for i := range f.fields {
+		result[f.fields[i].name] = f.fields[i].input.FormValue()
+	}
+	return result
+}
+
+// Stores the entered texts in the inputs histories
+func (f *Form) Commit() {
+	for i := range f.fields {
+		if textInput, ok := f.fields[i].input.(*TextInput); ok {
+			textInput.Commit()
+		}
+	}
+}
+
+func (f *Form) CapturesInput() bool {
+	return len(f.fields) > 0 && CapturesInput(f.fields[f.current].input)
+}
+
+func (f *Form) HandleInput(key input.KeyInput) {
+	if len(f.fields) == 0 {
+		return
+	}
+	if key.GetKey() == keyboard.KeyTab && !f.CapturesInput() {
+		f.Focus(f.current + 1)
+		return
+	}
+	f.fields[f.current].input.HandleInput(key)
+	f.RequestRedraw()
+}
+
+func (f *Form) Draw() {
+	for i := range f.fields {
+		GotoXY(f.rect.x, f.rect.y+uint16(i))
+		WriteFill(f.fields[i].label, f.labelWidth)
+		f.fields[i].input.Draw()
+	}
+
+	GotoXY(f.rect.x, f.rect.y+uint16(len(f.fields)))
+	WriteFill("", f.rect.w)
+	GotoXY(f.rect.x, f.rect.y+uint16(len(f.fields))+1)
+	if f.err != "" {
+		ErrorStyle()
+		WriteFill(f.err, f.rect.w)
+		Reset()
+	} else {
+		WriteFill("tab: next field, enter: accept, esc: cancel", f.rect.w)
+	}
+
+	// open dropdowns go over the fields below
+	for i := range f.fields {
+		if selectView, ok := f.fields[i].input.(*Select); ok {
+			selectView.DrawDropdown()
+		}
+	}
+}
+
+type DialogHandler func(result FormResult)
+
+/**
+	A popup wrapping a form, the handler gets the form values
+	once accepted with enter and validated
+**/
+type Dialog struct {
+	ViewImpl
+	title   string
+	form    *Form
+	width   uint16
+	handler DialogHandler
+	app     *Application
+}
+
+func DialogNew(title string, form *Form, width uint16, handler DialogHandler) *Dialog {
+	var dialog = Dialog{title: title, form: form, width: width, handler: handler}
+	dialog.Init()
+	return &dialog
+}
+
+func (d *Dialog) SetRect(rect Rect) {
+	d.ViewImpl.SetRect(rect)
+	d.form.SetRect(rect)
+}
+
+func (d *Dialog) CapturesInput() bool {
+	return d.form.CapturesInput()
+}
+
+func (d *Dialog) HandleInput(key input.KeyInput) {
+	if key.GetKey() == keyboard.KeyEnter && !d.form.CapturesInput() {
+		d.Submit()
+		return
+	}
+	d.form.HandleInput(key)
+}
+
+func (d *Dialog) Submit() {
+	if !d.form.Validate() {
+		return
+	}
+	d.form.Commit()
+	if d.app != nil {
+		d.app.ClosePopup()
+	}
+	d.handler(d.form.Result())
+}
+
+func (d *Dialog) Draw() {
+	d.form.Draw()
+}
+
+/**
+	Shows a dialog as a popup, centered on the screen
+**/
+func (a *Application) ShowDialog(dialog *Dialog) {
+	dialog.app = a
+
+	var container = TitledContainerNew(dialog.title, dialog, true)
+	container.Border = LineBorder
+	a.ShowPopupWithLayout(container, CenteredLayout(container, dialog.width, dialog.form.Height()+2), nil)
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/clidockermgr/input"
+	"github.com/eiannone/keyboard"
+)
+
+/**
+	A named action, like container.logs, with the keys it is bound to
+**/
+type Action struct {
+	Name        string
+	Description string
+	Keys        []input.KeyInput
+}
+
+func (a *Action) KeyNames() string {
+	var names = make([]string, len(a.Keys))
+	for i := range a.Keys {
+		names[i] = input.KeyName(a.Keys[i])
+	}
+	return strings.Join(names, ", ")
+}
+
+func (a *Action) Matches(key input.KeyInput) bool {
+	for i := range a.Keys {
+		if a.Keys[i] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// The part of the name before the first dot
+func (a *Action) Section() string {
+	return strings.SplitN(a.Name, ".", 2)[0]
+}
+
+/**
+	Maps action names to keys. Actions are defined with their
+	default keys and can be remapped afterwards, views bind their
+	handlers to action names so they follow the active mapping.
+**/
+type Keymap struct {
+	actions  []*Action
+	reserved []*Action
+	byName   map[string]*Action
+	sections map[string]string
+}
+
+/**
+	Creates a keymap with the actions handled by the application
+**/
+func KeymapNew() *Keymap {
+	var keymap = Keymap{
+		byName:   make(map[string]*Action),
+		sections: make(map[string]string),
+	}
+	keymap.DefineSection("app", "Everywhere")
+	keymap.Define("app.next", "Switches focus between views", input.KeyInputKey(keyboard.KeyTab))
+	keymap.Define("app.close", "Closes the active popup, or exits the application", input.KeyInputKey(keyboard.KeyEsc))
+	keymap.Define("app.help", "Shows the help", input.KeyInputChar('h'))
+	keymap.Define("app.grow", "Grows the focused view", input.KeyInputChar('+'))
+	keymap.Define("app.shrink", "Shrinks the focused view", input.KeyInputChar('-'))
+	keymap.Define("app.reset-sizes", "Restores the size of views", input.KeyInputChar('='))
+	return &keymap
+}
+
+func (k *Keymap) Define(name string, description string, keys ...input.KeyInput) {
+	var action = Action{Name: name, Description: description, Keys: keys}
+	k.actions = append(k.actions, &action)
+	k.byName[name] = &action
+}
+
+// Title shown in the help for actions starting with the given name
+func (k *Keymap) DefineSection(name string, title string) {
+	k.sections[name] = title
+}
+
+/**
+	Keys the views of a section handle themselves, like arrows
+	in lists, which none of its actions can be mapped to
+**/
+func (k *Keymap) Reserve(section string, description string, keys ...input.KeyInput) {
+	k.reserved = append(k.reserved, &Action{Name: section, Description: description, Keys: keys})
+}
+
+func (k *Keymap) Action(name string) *Action {
+	return k.byName[name]
+}
+
+func (k *Keymap) Remap(name string, keys []input.KeyInput) error {
+	var action = k.byName[name]
+	if action == nil {
+		return fmt.Errorf("unknown action '%s'", name)
+	}
+	action.Keys = keys
+	return nil
+}
+
+/**
+	Remaps actions from key names, as read from the config file
+**/
+func (k *Keymap) RemapNames(bindings map[string][]string) error {
+	var names = make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var keys []input.KeyInput
+		for _, keyName := range bindings[name] {
+			key, err := input.ParseKey(keyName)
+			if err != nil {
+				return fmt.Errorf("%s: %s", name, err)
+			}
+			keys = append(keys, key)
+		}
+		if err := k.Remap(name, keys); err != nil {
+			return err
+		}
+	}
+	return k.CheckConflicts()
+}
+
+/**
+	Actions of a section are bound to the same view, and the ones of
+	the app section to every view, so they can not share keys. The last
+	bound handler would otherwise silently take the key. Reserved keys
+	are handled by the views before any action, which would never run.
+**/
+func (k *Keymap) CheckConflicts() error {
+	for i, action := range k.actions {
+		for _, other := range k.actions[i+1:] {
+			if action.Section() != other.Section() && action.Section() != "app" && other.Section() != "app" {
+				continue
+			}
+			for _, key := range action.Keys {
+				if k.Matches(other.Name, key) {
+					return fmt.Errorf("%s is bound to both %s and %s", input.KeyName(key), action.Name, other.Name)
+				}
+			}
+		}
+		for _, reserved := range k.reserved {
+			if action.Section() != reserved.Section() && action.Section() != "app" {
+				continue
+			}
+			for _, key := range action.Keys {
+				if reserved.Matches(key) {
+					return fmt.Errorf("%s is bound to %s but is used for %s", input.KeyName(key), action.Name, reserved.Description)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (k *Keymap) Matches(name string, key input.KeyInput) bool {
+	var action = k.byName[name]
+	return action != nil && action.Matches(key)
+}
+
+/**
+	Binds the handler to the keys of the action, should be
+	called once the keymap has been loaded
+**/
+func (k *Keymap) Bind(view View, name string, handler KeyHandler) {
+	var action = k.byName[name]
+	if action == nil {
+		panic("Undefined action " + name)
+	}
+	for i := range action.Keys {
+		view.AddKeyHandler(action.Keys[i], handler)
+	}
+}
+
+/**
+	Views running an action on double clicks
+**/
+type DefaultActionView interface {
+	SetDefaultAction(handler KeyHandler)
+}
+
+/**
+	Binds the handler like Bind, double clicks on the view run
+	it as well, whatever keys the action is mapped to
+**/
+func (k *Keymap) BindDefault(view View, name string, handler KeyHandler) {
+	k.Bind(view, name, handler)
+	if target, ok := view.(DefaultActionView); ok {
+		target.SetDefaultAction(handler)
+	}
+}
+
+/**
+	Describes the active bindings, grouped by section
+**/
+func (k *Keymap) Help() string {
+	var builder strings.Builder
+	var section = ""
+
+	for _, action := range k.actions {
+		if action.Section() != section {
+			section = action.Section()
+			var title = k.sections[section]
+			if title == "" {
+				title = section
+			}
+			fmt.Fprintf(&builder, "    %s:\n", title)
+		}
+		var keys = action.KeyNames()
+		if keys == "" {
+			keys = "(unbound)"
+		}
+		fmt.Fprintf(&builder, "        %s: %s [%s]\n", keys, action.Description, action.Name)
+	}
+	return builder.String()
+}
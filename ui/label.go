@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 /**
@@ -10,7 +11,9 @@ import (
 **/
 type Label struct {
 	ViewImpl
-	text string
+	mutex sync.Mutex
+	text  string
+	Style TextStyle
 }
 
 func LabelNew(text string) *Label {
@@ -19,11 +22,28 @@ func LabelNew(text string) *Label {
 	return &label
 }
 
+func (l *Label) SetText(text string, style TextStyle) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.text = text
+	l.Style = style
+	l.RequestRedraw()
+}
+
 func (l *Label) Draw() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	GotoXY(l.rect.x, l.rect.y)
+	if l.Style != nil {
+		l.Style()
+	}
 	if len(l.text) > int(l.rect.w) {
 		fmt.Print(l.text[0:l.rect.w])
 	} else {
 		fmt.Printf("%s%s", l.text, strings.Repeat(" ", (int(l.rect.w)-len(l.text))))
 	}
+	if l.Style != nil {
+		Reset()
+	}
 }
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"github.com/clidockermgr/util"
+)
+
+type SizeKind int
+
+const (
+	SizeFixed SizeKind = iota
+	SizePercent
+	SizeFlex
+)
+
+/**
+	How much room a part of a layout takes, either a fixed
+	number of cells, a percentage of the whole, or a share
+	of what is left after fixed and percentage sizes.
+**/
+type Size struct {
+	Kind  SizeKind
+	Value int
+}
+
+func Fixed(cells int) Size {
+	return Size{SizeFixed, cells}
+}
+
+func Percent(percent int) Size {
+	return Size{SizePercent, percent}
+}
+
+func Flex(weight int) Size {
+	return Size{SizeFlex, weight}
+}
+
+// Smallest size a pane can be shrunk to
+const MinPaneSize = 2
+
+/**
+	Anything which can be placed on the screen, views and layouts
+**/
+type Placeable interface {
+	SetRect(rect Rect)
+}
+
+/**
+	Interface for layouts, which place their children
+	inside the rect they are given
+**/
+type Layout interface {
+	Placeable
+	// Grows or shrinks the part holding the view, returns false if not found
+	Resize(view View, delta int) bool
+	// Goes back to the sizes given when the children were added
+	ResetSizes()
+}
+
+/**
+	Splits a length between the given sizes
+**/
+func Distribute(total int, sizes []Size) []int {
+	var lengths = make([]int, len(sizes))
+	var remaining = total
+	var weights = 0
+
+	for i, size := range sizes {
+		switch size.Kind {
+		case SizeFixed:
+			lengths[i] = size.Value
+		case SizePercent:
+			lengths[i] = total * size.Value / 100
+		case SizeFlex:
+			weights += size.Value
+			continue
+		}
+		lengths[i] = util.Max(0, util.Min(lengths[i], remaining))
+		remaining -= lengths[i]
+	}
+
+	if weights <= 0 {
+		return lengths
+	}
+
+	var flexTotal = remaining
+	for i, size := range sizes {
+		if size.Kind == SizeFlex {
+			lengths[i] = flexTotal * size.Value / weights
+			remaining -= lengths[i]
+		}
+	}
+	// rounding leftovers are spread one cell at a time
+	for i := 0; remaining > 0; i = (i + 1) % len(sizes) {
+		if sizes[i].Kind == SizeFlex && sizes[i].Value > 0 {
+			lengths[i]++
+			remaining--
+		}
+	}
+	return lengths
+}
+
+type layoutEntry struct {
+	item    Placeable
+	size    Size
+	initial Size
+	length  int
+}
+
+// Resized parts become fixed, the ones which were fixed from the start keep their size
+func (e *layoutEntry) resized() bool {
+	return e.size.Kind == SizeFixed && e.initial.Kind != SizeFixed
+}
+
+func (e *layoutEntry) minLength() int {
+	if e.initial.Kind == SizeFixed {
+		return e.initial.Value
+	}
+	return MinPaneSize
+}
+
+/**
+	Gives a part the wanted length as long as the others keep at least
+	their minimum, shrinking other resized parts to make room if needed
+**/
+func resizeEntries(entries []*layoutEntry, total int, index int, length int) {
+	var room = total
+	for i, entry := range entries {
+		if i != index {
+			room -= entry.minLength()
+		}
+	}
+	length = util.Max(MinPaneSize, util.Min(length, room))
+	entries[index].size = Fixed(length)
+
+	var left = util.Max(0, room-length)
+	for i, entry := range entries {
+		if i != index && entry.resized() {
+			var extra = util.Min(entry.size.Value-MinPaneSize, left)
+			entry.size = Fixed(MinPaneSize + extra)
+			left -= extra
+		}
+	}
+}
+
+func (e *layoutEntry) ResetSizes() {
+	e.size = e.initial
+	if layout, ok := e.item.(Layout); ok {
+		layout.ResetSizes()
+	}
+}
+
+/**
+	A layout which stacks its children, vertically or horizontally
+**/
+type Box struct {
+	vertical bool
+	entries  []*layoutEntry
+	rect     Rect
+}
+
+func VBoxNew() *Box {
+	return &Box{vertical: true}
+}
+
+func HBoxNew() *Box {
+	return &Box{vertical: false}
+}
+
+func (b *Box) Add(item Placeable, size Size) *Box {
+	b.entries = append(b.entries, &layoutEntry{item: item, size: size, initial: size})
+	return b
+}
+
+func (b *Box) length() int {
+	if b.vertical {
+		return int(b.rect.h)
+	}
+	return int(b.rect.w)
+}
+
+func (b *Box) SetRect(rect Rect) {
+	b.rect = rect
+
+	var sizes = make([]Size, len(b.entries))
+	for i, entry := range b.entries {
+		sizes[i] = entry.size
+	}
+
+	var pos = 0
+	for i, length := range Distribute(b.length(), sizes) {
+		var entry = b.entries[i]
+		entry.length = length
+		if b.vertical {
+			entry.item.SetRect(Rect{rect.x, rect.y + uint16(pos), rect.w, uint16(length)})
+		} else {
+			entry.item.SetRect(Rect{rect.x + uint16(pos), rect.y, uint16(length), rect.h})
+		}
+		pos += length
+	}
+}
+
+func (b *Box) Resize(view View, delta int) bool {
+	for i, entry := range b.entries {
+		if entry.item == view {
+			resizeEntries(b.entries, b.length(), i, entry.length+delta)
+			b.SetRect(b.rect)
+			return true
+		}
+		if layout, ok := entry.item.(Layout); ok && layout.Resize(view, delta) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Box) ResetSizes() {
+	for _, entry := range b.entries {
+		entry.ResetSizes()
+	}
+	b.SetRect(b.rect)
+}
+
+type gridCell struct {
+	item   Placeable
+	row    int
+	column int
+}
+
+/**
+	A layout which places its children in cells, resizing
+	a child changes the height of the row it is in.
+**/
+type Grid struct {
+	rows    []*layoutEntry
+	columns []Size
+	cells   []gridCell
+	rect    Rect
+}
+
+func GridNew(rows []Size, columns []Size) *Grid {
+	var grid = Grid{columns: columns}
+	for _, size := range rows {
+		grid.rows = append(grid.rows, &layoutEntry{size: size, initial: size})
+	}
+	return &grid
+}
+
+func (g *Grid) Add(item Placeable, row, column int) *Grid {
+	g.cells = append(g.cells, gridCell{item, row, column})
+	return g
+}
+
+func (g *Grid) SetRect(rect Rect) {
+	g.rect = rect
+
+	var rowSizes = make([]Size, len(g.rows))
+	for i, row := range g.rows {
+		rowSizes[i] = row.size
+	}
+	var heights = Distribute(int(rect.h), rowSizes)
+	var widths = Distribute(int(rect.w), g.columns)
+
+	for i, row := range g.rows {
+		row.length = heights[i]
+	}
+
+	for _, cell := range g.cells {
+		if cell.row >= len(heights) || cell.column >= len(widths) {
+			continue
+		}
+		var x, y = 0, 0
+		for i := 0; i < cell.column; i++ {
+			x += widths[i]
+		}
+		for i := 0; i < cell.row; i++ {
+			y += heights[i]
+		}
+		var cellRect = Rect{rect.x + uint16(x), rect.y + uint16(y), uint16(widths[cell.column]), uint16(heights[cell.row])}
+		cell.item.SetRect(cellRect)
+	}
+}
+
+func (g *Grid) Resize(view View, delta int) bool {
+	for _, cell := range g.cells {
+		if cell.item == view && cell.row < len(g.rows) {
+			resizeEntries(g.rows, int(g.rect.h), cell.row, g.rows[cell.row].length+delta)
+			g.SetRect(g.rect)
+			return true
+		}
+		if layout, ok := cell.item.(Layout); ok && layout.Resize(view, delta) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Grid) ResetSizes() {
+	for _, row := range g.rows {
+		row.size = row.initial
+	}
+	for _, cell := range g.cells {
+		if layout, ok := cell.item.(Layout); ok {
+			layout.ResetSizes()
+		}
+	}
+	g.SetRect(g.rect)
+}
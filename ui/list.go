@@ -16,6 +16,14 @@ type ListItem interface {
 	Value() interface{}
 }
 
+/**
+	Items with an identity stay selected when
+	the model changes their order
+**/
+type KeyedItem interface {
+	Key() string
+}
+
 type ListModelListener func()
 
 /**
@@ -74,6 +82,38 @@ func (m *BaseListModel) SetProperty(property int, value interface{}) {}
 
 func (m *BaseListModel) Update() {}
 
+/**
+	A list model for plain strings
+**/
+type StringItem string
+
+func (i StringItem) String() string {
+	return string(i)
+}
+
+func (i StringItem) Value() interface{} {
+	return string(i)
+}
+
+type StringListModel struct {
+	BaseListModel
+	items []string
+}
+
+func StringListModelNew(items []string) *StringListModel {
+	var model = StringListModel{items: items}
+	model.Init()
+	return &model
+}
+
+func (m *StringListModel) ItemCount() int {
+	return len(m.items)
+}
+
+func (m *StringListModel) Item(index int) ListItem {
+	return StringItem(m.items[index])
+}
+
 /**
 	A list component
 **/
@@ -82,6 +122,8 @@ type List struct {
 	Model         ListModel
 	startIndex    int
 	selectedIndex int
+	selectedKey   string
+	defaultAction KeyHandler
 }
 
 func ListNew() *List {
@@ -108,26 +150,40 @@ func (l *List) SelectedItem() ListItem {
 	return l.Model.Item(l.selectedIndex)
 }
 
+// Keeps the selected item visible when the list gets shorter
+func (l *List) SetRect(rect Rect) {
+	l.ViewImpl.SetRect(rect)
+	if l.rect.h > 0 && l.selectedIndex-l.startIndex >= int(l.rect.h) {
+		l.startIndex = l.selectedIndex - int(l.rect.h) + 1
+	}
+}
+
 func (l *List) Draw() {
 	GotoXY(l.rect.x, l.rect.y)
 
 	var y uint16 = 0
 
-	for i := l.startIndex; i < l.Model.ItemCount() && y <= l.rect.h; i++ {
+	var style = CurrentTheme.Pane
+	if l.focused {
+		style = CurrentTheme.FocusedPane
+	}
+
+	for i := l.startIndex; i < l.Model.ItemCount() && y < l.rect.h; i++ {
 		GotoXY(l.rect.x, l.rect.y+y)
 		var text = l.Model.Item(i)
+		style.Apply()
 		if l.focused && l.selectedIndex == i {
-			UnderlineOn()
+			CurrentTheme.Selection.Apply()
 		}
 		WriteFill(text.String(), l.rect.w)
 		Reset()
 		y++
 	}
-	if y < l.rect.h {
-		for ; y <= l.rect.h; y++ {
-			GotoXY(l.rect.x, l.rect.y+y)
-			WriteFill("", l.rect.w)
-		}
+	for ; y < l.rect.h; y++ {
+		GotoXY(l.rect.x, l.rect.y+y)
+		style.Apply()
+		WriteFill("", l.rect.w)
+		Reset()
 	}
 }
 
@@ -135,10 +191,11 @@ func (l *List) ScrollFwd() {
 	if l.selectedIndex < l.Model.ItemCount()-1 {
 		l.selectedIndex++
 
-		if l.selectedIndex-l.startIndex > int(l.rect.h) {
+		if l.selectedIndex-l.startIndex >= int(l.rect.h) {
 			l.startIndex++
 		}
 	}
+	l.rememberSelection()
 }
 
 func (l *List) ScrollBack() {
@@ -148,8 +205,57 @@ func (l *List) ScrollBack() {
 	if l.startIndex > l.selectedIndex {
 		l.startIndex = l.selectedIndex
 	}
+	l.rememberSelection()
+}
+
+func (l *List) itemKey(index int) string {
+	if index < 0 || index >= l.Model.ItemCount() {
+		return ""
+	}
+	if item, ok := l.Model.Item(index).(KeyedItem); ok {
+		return item.Key()
+	}
+	return ""
+}
+
+func (l *List) rememberSelection() {
+	l.selectedKey = l.itemKey(l.selectedIndex)
 }
 
+/**
+	Moves the selection to wherever the selected item went,
+	staying at the same index when it is gone
+**/
+func (l *List) restoreSelection() {
+	var count = l.Model.ItemCount()
+
+	if l.selectedKey != "" && l.itemKey(l.selectedIndex) != l.selectedKey {
+		for i := 0; i < count; i++ {
+			if l.itemKey(i) == l.selectedKey {
+				l.selectedIndex = i
+				break
+			}
+		}
+	}
+	if l.selectedIndex >= count {
+		l.selectedIndex = count - 1
+	}
+	if l.selectedIndex < 0 {
+		l.selectedIndex = 0
+	}
+
+	if l.startIndex > l.selectedIndex {
+		l.startIndex = l.selectedIndex
+	}
+	if l.rect.h > 0 && l.selectedIndex-l.startIndex >= int(l.rect.h) {
+		l.startIndex = l.selectedIndex - int(l.rect.h) + 1
+	}
+	l.rememberSelection()
+}
+
+// Keys lists handle themselves, to move the selection
+var ListKeys = []input.KeyInput{input.KeyInputKey(keyboard.KeyArrowUp), input.KeyInputKey(keyboard.KeyArrowDown)}
+
 func (l *List) HandleInput(input input.KeyInput) {
 
 	switch input.GetKey() {
@@ -164,6 +270,50 @@ func (l *List) HandleInput(input input.KeyInput) {
 	}
 }
 
+/**
+	Clicks select a row, double clicks act like enter,
+	and the wheel moves the selection
+**/
+func (l *List) HandleMouse(mouse input.MouseInput) {
+	switch mouse.Button {
+	case input.MouseWheelUp:
+		l.ScrollBack()
+	case input.MouseWheelDown:
+		l.ScrollFwd()
+	case input.MouseLeft:
+		if !mouse.Pressed {
+			return
+		}
+		var index = l.startIndex + int(mouse.Y-l.rect.y)
+		if index >= l.Model.ItemCount() {
+			return
+		}
+		l.selectedIndex = index
+		l.rememberSelection()
+		if mouse.DoubleClick {
+			l.RunDefaultAction()
+		}
+	default:
+		return
+	}
+	l.RequestRedraw()
+}
+
+// Lists without a default action take double clicks as enter
+func (l *List) SetDefaultAction(handler KeyHandler) {
+	l.defaultAction = handler
+}
+
+func (l *List) RunDefaultAction() {
+	var enter = input.KeyInputKey(keyboard.KeyEnter)
+	if l.defaultAction != nil {
+		l.defaultAction(enter)
+	} else {
+		l.HandleInput(enter)
+	}
+}
+
 func (l *List) Changed() {
+	l.restoreSelection()
 	l.RequestRedraw()
 }
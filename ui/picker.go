@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"github.com/clidockermgr/input"
+	"github.com/eiannone/keyboard"
+)
+
+type PickHandler func(value string)
+
+/**
+	Lets the user pick one option from a list, or type
+	a value. A typed value takes precedence when present.
+**/
+type Picker struct {
+	ViewImpl
+	textInput *TextInput
+	list      *List
+	handler   PickHandler
+}
+
+func PickerNew(options []string, handler PickHandler) *Picker {
+	var picker = Picker{
+		textInput: TextInputNew(""),
+		list:      ListNew(),
+		handler:   handler,
+	}
+	picker.Init()
+	picker.list.SetModel(StringListModelNew(options))
+	picker.list.SetFocused(true)
+	picker.textInput.SetFocused(true)
+	return &picker
+}
+
+func (p *Picker) SetRect(rect Rect) {
+	p.ViewImpl.SetRect(rect)
+	p.textInput.SetRect(Rect{rect.x, rect.y, rect.w, 1})
+	if rect.h > 2 {
+		p.list.SetRect(Rect{rect.x, rect.y + 2, rect.w, rect.h - 2})
+	}
+}
+
+func (p *Picker) Value() string {
+	var text = p.textInput.Text()
+	if text != "" {
+		return text
+	}
+	if p.list.Model.ItemCount() == 0 {
+		return ""
+	}
+	return p.list.SelectedItem().Value().(string)
+}
+
+// A double click on the list picks the option, as long as nothing was typed
+func (p *Picker) HandleMouse(mouse input.MouseInput) {
+	HandleMouse(p.list, mouse)
+	if mouse.DoubleClick && p.list.Contains(mouse.X, mouse.Y) {
+		p.HandleInput(input.KeyInputKey(keyboard.KeyEnter))
+	}
+}
+
+func (p *Picker) HandleInput(key input.KeyInput) {
+	switch key.GetKey() {
+	case keyboard.KeyArrowUp, keyboard.KeyArrowDown:
+		p.list.HandleInput(key)
+	case keyboard.KeyEnter:
+		var value = p.Value()
+		if value != "" {
+			p.handler(value)
+		}
+	default:
+		p.textInput.HandleInput(key)
+	}
+}
+
+func (p *Picker) CheckRedrawFlag() bool {
+	var dirty = p.ViewImpl.CheckRedrawFlag()
+	var inputDirty = p.textInput.CheckRedrawFlag()
+	var listDirty = p.list.CheckRedrawFlag()
+	return dirty || inputDirty || listDirty
+}
+
+func (p *Picker) Draw() {
+	GotoXY(p.rect.x, p.rect.y+1)
+	WriteFill("", p.rect.w)
+	p.textInput.Draw()
+	p.list.Draw()
+}
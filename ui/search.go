@@ -0,0 +1,266 @@
+package ui
+
+import (
+	"regexp"
+
+	"github.com/clidockermgr/input"
+	"github.com/eiannone/keyboard"
+)
+
+var MatchStyle TextStyle = func() {
+	CurrentTheme.Match.Apply()
+}
+
+var CurrentMatchStyle TextStyle = func() {
+	CurrentTheme.CurrentMatch.Apply()
+}
+
+/**
+	State of the incremental search of a text view
+**/
+type TextSearch struct {
+	editing         bool
+	backward        bool
+	regex           bool
+	caseInsensitive bool
+	query           []rune
+	pattern         *regexp.Regexp
+	invalid         bool
+	matchLine       int
+	matchStart      int
+	matchEnd        int
+	originLine      int
+	originYpos      uint16
+	originXpos      uint16
+}
+
+func (s *TextSearch) Compile() {
+	s.pattern = nil
+	s.invalid = false
+
+	if len(s.query) == 0 {
+		return
+	}
+
+	var expression = string(s.query)
+
+	if !s.regex {
+		expression = regexp.QuoteMeta(expression)
+	}
+	if s.caseInsensitive {
+		expression = "(?i)" + expression
+	}
+
+	pattern, err := regexp.Compile(expression)
+
+	if err != nil {
+		s.invalid = true
+		return
+	}
+	s.pattern = pattern
+}
+
+func (s *TextSearch) Prompt() string {
+	var prompt = "/"
+	if s.backward {
+		prompt = "?"
+	}
+	if s.regex {
+		prompt += "[regex]"
+	}
+	if s.caseInsensitive {
+		prompt += "[nocase]"
+	}
+	prompt += string(s.query)
+	if s.invalid {
+		prompt += "  (invalid expression)"
+	} else if s.pattern != nil && s.matchLine == -1 {
+		prompt += "  (not found)"
+	}
+	return prompt
+}
+
+func (t *TextView) CapturesInput() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.search.editing
+}
+
+func (t *TextView) StartSearch(backward bool) {
+	t.search.editing = true
+	t.search.backward = backward
+	t.search.query = nil
+	t.search.pattern = nil
+	t.search.invalid = false
+	t.search.matchLine = -1
+	t.search.originLine = int(t.ypos)
+	t.search.originYpos = t.ypos
+	t.search.originXpos = t.xpos
+	t.RequestRedraw()
+}
+
+/**
+	Handles keys while the search query is being typed, matches are
+	looked up after each change starting from where the search began.
+**/
+func (t *TextView) HandleSearchInput(key input.KeyInput) {
+	switch key.GetKey() {
+	case keyboard.KeyEnter:
+		t.search.editing = false
+	case keyboard.KeyEsc:
+		t.search.editing = false
+		t.search.pattern = nil
+		t.search.matchLine = -1
+		t.ypos = t.search.originYpos
+		t.xpos = t.search.originXpos
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		if len(t.search.query) > 0 {
+			t.search.query = t.search.query[0 : len(t.search.query)-1]
+			t.UpdateSearch()
+		}
+	case keyboard.KeyCtrlR:
+		t.search.regex = !t.search.regex
+		t.UpdateSearch()
+	case keyboard.KeyCtrlE:
+		t.search.caseInsensitive = !t.search.caseInsensitive
+		t.UpdateSearch()
+	case keyboard.KeySpace:
+		t.search.query = append(t.search.query, ' ')
+		t.UpdateSearch()
+	default:
+		if key.GetChar() != 0 {
+			t.search.query = append(t.search.query, key.GetChar())
+			t.UpdateSearch()
+		}
+	}
+	t.RequestRedraw()
+}
+
+func (t *TextView) UpdateSearch() {
+	if t.search.originLine >= len(t.text) {
+		t.search.originLine = len(t.text) - 1
+	}
+
+	t.search.Compile()
+	t.search.matchLine = -1
+
+	if t.search.pattern == nil {
+		t.ypos = t.search.originYpos
+		t.xpos = t.search.originXpos
+		return
+	}
+
+	if t.search.backward {
+		t.FindMatch(t.search.originLine, len(t.text[t.search.originLine])+1, true)
+	} else {
+		t.FindMatch(t.search.originLine, 0, false)
+	}
+}
+
+/**
+	Jumps to the next match in the search direction, or
+	in the opposite one when reverse is set.
+**/
+func (t *TextView) NextMatch(reverse bool) {
+	if t.search.pattern == nil {
+		return
+	}
+
+	var backward = t.search.backward != reverse
+	var line = t.search.matchLine
+	var column = t.search.matchStart + 1
+
+	if line == -1 {
+		line = int(t.ypos)
+		column = 0
+	} else if backward {
+		column = t.search.matchStart
+	}
+
+	t.FindMatch(line, column, backward)
+	t.RequestRedraw()
+}
+
+/**
+	Looks for a match starting at the given line and column,
+	wrapping around the text. Backward searches look for the
+	last match starting before the column.
+**/
+func (t *TextView) FindMatch(line int, column int, backward bool) bool {
+	var count = len(t.text)
+
+	for i := 0; i <= count; i++ {
+		var index = line + i
+		if backward {
+			index = line - i
+		}
+		index = ((index % count) + count) % count
+
+		var matches = t.search.pattern.FindAllStringIndex(t.text[index], -1)
+
+		if backward {
+			for m := len(matches) - 1; m >= 0; m-- {
+				if i > 0 || matches[m][0] < column {
+					t.SetMatch(index, matches[m])
+					return true
+				}
+			}
+		} else {
+			for m := range matches {
+				if i > 0 || matches[m][0] >= column {
+					t.SetMatch(index, matches[m])
+					return true
+				}
+			}
+		}
+	}
+	t.search.matchLine = -1
+	return false
+}
+
+func (t *TextView) SetMatch(line int, match []int) {
+	t.search.matchLine = line
+	t.search.matchStart = match[0]
+	t.search.matchEnd = match[1]
+	t.follow = false
+	t.ScrollToMatch()
+}
+
+func (t *TextView) ScrollToMatch() {
+	var height = int(t.rect.h)
+	var width = int(t.rect.w)
+
+	if t.search.matchLine < int(t.ypos) || t.search.matchLine >= int(t.ypos)+height-1 {
+		var ypos = t.search.matchLine - height/2
+		if ypos < 0 {
+			ypos = 0
+		}
+		t.ypos = uint16(ypos)
+	}
+
+	if t.search.matchStart < int(t.xpos) || t.search.matchEnd > int(t.xpos)+width {
+		var xpos = t.search.matchStart - width/4
+		if xpos < 0 {
+			xpos = 0
+		}
+		t.xpos = uint16(xpos)
+	}
+}
+
+/**
+	Returns the ranges of the line to be highlighted,
+	the current match is returned as a separate range.
+**/
+func (t *TextView) LineMatches(line int) ([][]int, []int) {
+	if t.search.pattern == nil {
+		return nil, nil
+	}
+
+	var matches = t.search.pattern.FindAllStringIndex(t.text[line], -1)
+	var current []int
+
+	if line == t.search.matchLine {
+		current = []int{t.search.matchStart, t.search.matchEnd}
+	}
+	return matches, current
+}
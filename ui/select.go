@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/util"
+	"github.com/eiannone/keyboard"
+)
+
+/**
+	A dropdown to choose one of a fixed set of options. Left and
+	right cycle the options, space opens the dropdown and enter
+	or space picks the highlighted option.
+**/
+type Select struct {
+	ViewImpl
+	options  []string
+	selected int
+	open     bool
+	cursor   int
+}
+
+func SelectNew(options []string, selected string) *Select {
+	var selectView = Select{options: options}
+	selectView.Init()
+	selectView.SetSelected(selected)
+	return &selectView
+}
+
+func (s *Select) Selected() string {
+	if len(s.options) == 0 {
+		return ""
+	}
+	return s.options[s.selected]
+}
+
+func (s *Select) SetSelected(value string) {
+	for i := range s.options {
+		if s.options[i] == value {
+			s.selected = i
+		}
+	}
+	s.RequestRedraw()
+}
+
+func (s *Select) FormValue() interface{} {
+	return s.Selected()
+}
+
+func (s *Select) CapturesInput() bool {
+	return s.open
+}
+
+func (s *Select) HandleInput(key input.KeyInput) {
+	var count = len(s.options)
+
+	if count == 0 {
+		s.ViewImpl.HandleInput(key)
+		return
+	}
+
+	if s.open {
+		switch key.GetKey() {
+		case keyboard.KeyArrowUp:
+			s.cursor = (s.cursor + count - 1) % count
+		case keyboard.KeyArrowDown, keyboard.KeyTab:
+			s.cursor = (s.cursor + 1) % count
+		case keyboard.KeyEnter, keyboard.KeySpace:
+			s.selected = s.cursor
+			s.open = false
+		case keyboard.KeyEsc:
+			s.open = false
+		}
+		s.RequestRedraw()
+		return
+	}
+
+	switch key.GetKey() {
+	case keyboard.KeyArrowLeft:
+		s.selected = (s.selected + count - 1) % count
+	case keyboard.KeyArrowRight:
+		s.selected = (s.selected + 1) % count
+	case keyboard.KeySpace:
+		s.open = true
+		s.cursor = s.selected
+	default:
+		s.ViewImpl.HandleInput(key)
+		return
+	}
+	s.RequestRedraw()
+}
+
+func (s *Select) Draw() {
+	GotoXY(s.rect.x, s.rect.y)
+
+	var text = "< " + s.Selected() + " >"
+	if s.focused {
+		CurrentTheme.Control.Apply()
+		WriteFill(text, uint16(util.Min(len(text), int(s.rect.w))))
+		Reset()
+		WriteFill("", uint16(util.Max(0, int(s.rect.w)-len(text))))
+	} else {
+		WriteFill(text, s.rect.w)
+	}
+}
+
+/**
+	Draws the open dropdown below the view, over
+	whatever was drawn there.
+**/
+func (s *Select) DrawDropdown() {
+	if !s.open {
+		return
+	}
+
+	var width uint16 = 0
+	for i := range s.options {
+		if uint16(len(s.options[i])+2) > width {
+			width = uint16(len(s.options[i]) + 2)
+		}
+	}
+
+	for i := range s.options {
+		GotoXY(s.rect.x, s.rect.y+1+uint16(i))
+		if i == s.cursor {
+			CurrentTheme.Control.Apply()
+		} else {
+			CurrentTheme.Dropdown.Apply()
+		}
+		WriteFill(" "+s.options[i], width)
+		Reset()
+	}
+}
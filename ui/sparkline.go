@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+)
+
+// Blocks from empty to full, each a cell eighth taller than the previous one
+var SparklineBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// Eighths of a cell filled by value, out of cells cells for max
+func sparklineLevel(value, max float64, cells int) int {
+	if max <= 0 || value <= 0 {
+		return 0
+	}
+	var eighths = cells * (len(SparklineBlocks) - 1)
+	var level = int(value / max * float64(eighths))
+	if level > eighths {
+		level = eighths
+	}
+	// Anything above zero shows
+	if level == 0 {
+		level = 1
+	}
+	return level
+}
+
+// The largest of the values, but never below the given floor
+func SparklineMax(values []float64, floor float64) float64 {
+	var max = floor
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
+
+/**
+	Draws the last values in one line of the given width,
+	right aligned so that the newest value is at the end
+**/
+func SparklineString(values []float64, width int, max float64) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var builder strings.Builder
+	builder.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, value := range values {
+		builder.WriteRune(SparklineBlocks[sparklineLevel(value, max, 1)])
+	}
+	return builder.String()
+}
+
+/**
+	A chart of the last values with a title line on top,
+	taking all the rows below it. Values can be updated
+	from any goroutine.
+**/
+type Sparkline struct {
+	ViewImpl
+	mutex  sync.Mutex
+	title  string
+	values []float64
+	max    float64
+}
+
+func SparklineNew(title string) *Sparkline {
+	var sparkline = Sparkline{title: title}
+	sparkline.Init()
+	return &sparkline
+}
+
+// Values are scaled so that max takes the whole height
+func (s *Sparkline) SetValues(title string, values []float64, max float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.title = title
+	s.values = values
+	s.max = max
+	s.RequestRedraw()
+}
+
+func (s *Sparkline) Draw() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.rect.h == 0 {
+		return
+	}
+
+	GotoXY(s.rect.x, s.rect.y)
+	CurrentTheme.Pane.Apply()
+	WriteFill(s.title, s.rect.w)
+	Reset()
+
+	var rows = int(s.rect.h) - 1
+	var values = s.values
+	if len(values) > int(s.rect.w) {
+		values = values[len(values)-int(s.rect.w):]
+	}
+	var padding = int(s.rect.w) - len(values)
+	var blocks = len(SparklineBlocks) - 1
+
+	for row := 0; row < rows; row++ {
+		// Rows are drawn top down, each showing the eighths above the rows below it
+		var below = (rows - row - 1) * blocks
+		var builder strings.Builder
+		builder.WriteString(strings.Repeat(" ", padding))
+		for _, value := range values {
+			var fill = sparklineLevel(value, s.max, rows) - below
+			if fill < 0 {
+				fill = 0
+			}
+			if fill > blocks {
+				fill = blocks
+			}
+			builder.WriteRune(SparklineBlocks[fill])
+		}
+		GotoXY(s.rect.x, s.rect.y+uint16(row)+1)
+		CurrentTheme.Chart.Apply()
+		WriteFill(builder.String(), s.rect.w)
+		Reset()
+	}
+}
@@ -1,26 +1,40 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/clidockermgr/input"
 	"github.com/clidockermgr/util"
 	"github.com/eiannone/keyboard"
 )
 
+// Sets the terminal attributes a line is drawn with
+type TextStyle func()
+
+/**
+	A scrollable text component. When following, the view
+	stays at the bottom while new text is appended.
+**/
 type TextView struct {
 	ViewImpl
+	mutex    sync.Mutex
 	text     []string
+	styles   []TextStyle
 	xpos     uint16
 	ypos     uint16
 	maxWidth uint16
+	follow   bool
+	search   TextSearch
 }
 
 func TextViewNew(text string) *TextView {
 
 	var textLines = strings.Split(text, "\n")
 
-	var textView = TextView{text: textLines}
+	var textView = TextView{text: textLines, styles: make([]TextStyle, len(textLines))}
+	textView.search.matchLine = -1
 
 	textView.Init()
 
@@ -31,54 +45,244 @@ func TextViewNew(text string) *TextView {
 	return &textView
 }
 
+func (t *TextView) SetFollow(follow bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.follow = follow
+	if follow {
+		t.gotoEnd()
+	}
+	t.RequestRedraw()
+}
+
+func (t *TextView) IsFollowing() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.follow
+}
+
+/**
+	Appends lines at the end of the text, safe to be called
+	from a goroutine other than the drawing one.
+**/
+func (t *TextView) Append(text string) {
+	t.AppendStyled(text, nil)
+}
+
+func (t *TextView) AppendStyled(text string, style TextStyle) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var textLines = strings.Split(text, "\n")
+
+	// the view starts with a single empty line, replace it
+	if len(t.text) == 1 && t.text[0] == "" {
+		t.text = nil
+		t.styles = nil
+	}
+
+	t.text = append(t.text, textLines...)
+	for range textLines {
+		t.styles = append(t.styles, style)
+	}
+
+	for r := range textLines {
+		t.maxWidth = uint16(util.Max(int(t.maxWidth), len(textLines[r])))
+	}
+
+	if t.follow {
+		t.gotoEnd()
+	}
+	t.RequestRedraw()
+}
+
+func (t *TextView) LineCount() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	// the single empty line of an empty view gets replaced when appending
+	if len(t.text) == 1 && t.text[0] == "" {
+		return 0
+	}
+	return len(t.text)
+}
+
+func (t *TextView) SetLineStyle(line int, style TextStyle) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if line >= 0 && line < len(t.styles) {
+		t.styles[line] = style
+		t.RequestRedraw()
+	}
+}
+
+/**
+	Replaces the whole text keeping the scroll position
+**/
+func (t *TextView) SetText(text string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.text = strings.Split(text, "\n")
+	t.styles = make([]TextStyle, len(t.text))
+	t.maxWidth = 0
+	for r := range t.text {
+		t.maxWidth = uint16(util.Max(int(t.maxWidth), len(t.text[r])))
+	}
+
+	if t.follow || int(t.ypos) >= len(t.text) {
+		t.gotoEnd()
+	}
+	t.RequestRedraw()
+}
+
+func (t *TextView) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.text = []string{""}
+	t.styles = []TextStyle{nil}
+	t.xpos = 0
+	t.ypos = 0
+	t.maxWidth = 0
+	t.RequestRedraw()
+}
+
+func (t *TextView) gotoEnd() {
+	t.ypos = uint16(util.Max(0, len(t.text)-int(t.rect.h)))
+}
+
+// Reaching the bottom resumes following
+func (t *TextView) checkAtEnd() {
+	if int(t.ypos+t.rect.h) >= len(t.text) {
+		t.follow = true
+	}
+}
+
+func (t *TextView) SetRect(rect Rect) {
+	t.ViewImpl.SetRect(rect)
+	if t.follow {
+		t.gotoEnd()
+	}
+}
+
 func (t *TextView) Draw() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	var y uint16 = 0
 
+	var height = t.rect.h
+	if t.search.editing && height > 0 {
+		height--
+	}
+
 	var firstLine = int(t.ypos)
-	var lastLine = int(t.ypos + t.rect.h - 1)
+	var lastLine = int(t.ypos + height - 1)
 
 	var length = len(t.text)
 
 	if lastLine >= length {
 		lastLine = length - 1
-		firstLine = util.Max(0, lastLine-int(t.rect.h))
+		firstLine = util.Max(0, lastLine-int(height))
 	}
 
-	for v := firstLine; v <= lastLine; v++ {
+	for v := firstLine; v <= lastLine && y < height; v++ {
 
 		GotoXY(t.rect.x, t.rect.y+y)
 
-		var line = t.text[v]
-		if t.xpos < uint16(len(line)) {
-			line = line[t.xpos:]
-		} else {
-			line = ""
-		}
-
-		WriteFill(line, t.rect.w)
+		t.DrawLine(v)
 
 		y++
-		if y >= t.rect.h {
-			break
-		}
 	}
-	for ; y < t.rect.h; y++ {
+	for ; y < height; y++ {
 		GotoXY(t.rect.x, t.rect.y+y)
 		WriteFill("", t.rect.w)
 	}
+	if t.search.editing {
+		GotoXY(t.rect.x, t.rect.y+y)
+		WriteFill(t.search.Prompt(), t.rect.w)
+	}
+}
+
+const (
+	highlightNone = iota
+	highlightMatch
+	highlightCurrent
+)
+
+/**
+	Draws the visible part of a line, highlighting search matches
+**/
+func (t *TextView) DrawLine(v int) {
+	var line = t.text[v]
+	var style = t.styles[v]
+
+	if t.xpos < uint16(len(line)) {
+		line = line[t.xpos:]
+	} else {
+		line = ""
+	}
+	if len(line) > int(t.rect.w) {
+		line = line[0:t.rect.w]
+	}
+
+	var highlights = make([]int, len(line))
+	var matches, current = t.LineMatches(v)
+
+	for _, match := range matches {
+		var highlight = highlightMatch
+		if current != nil && match[0] == current[0] {
+			highlight = highlightCurrent
+		}
+		for i := util.Max(0, match[0]-int(t.xpos)); i < util.Min(len(line), match[1]-int(t.xpos)); i++ {
+			highlights[i] = highlight
+		}
+	}
+
+	if style != nil {
+		style()
+	}
+
+	for start := 0; start < len(line); {
+		var end = start
+		for end < len(line) && highlights[end] == highlights[start] {
+			end++
+		}
+		switch highlights[start] {
+		case highlightMatch:
+			MatchStyle()
+		case highlightCurrent:
+			CurrentMatchStyle()
+		}
+		fmt.Print(line[start:end])
+		if highlights[start] != highlightNone {
+			Reset()
+			if style != nil {
+				style()
+			}
+		}
+		start = end
+	}
+	fmt.Print(strings.Repeat(" ", int(t.rect.w)-len(line)))
+
+	if style != nil {
+		Reset()
+	}
 }
 
 func (t *TextView) ScrollBack() {
 	if t.ypos > 0 {
 		t.ypos--
 	}
+	t.follow = false
 	t.RequestRedraw()
 }
 
 func (t *TextView) ScrollFwd() {
-	if t.ypos+t.rect.h < uint16(len(t.text))-1 {
+	if int(t.ypos+t.rect.h) < len(t.text) {
 		t.ypos++
 	}
+	t.checkAtEnd()
 	t.RequestRedraw()
 }
 
@@ -102,21 +306,84 @@ func (t *TextView) ScrollPageFwd() {
 
 	var length = len(t.text)
 
-	if ypos+int(t.rect.h) > length-1 {
-		ypos = length - int(t.rect.h) - 1
+	if ypos+int(t.rect.h) > length {
+		ypos = util.Max(0, length-int(t.rect.h))
 	}
 
 	t.ypos = uint16(ypos)
+	t.checkAtEnd()
 	t.RequestRedraw()
 }
 
 func (t *TextView) ScrollPageBack() {
 	var ypos int = util.Max(0, int(t.ypos)-int(t.rect.h))
 	t.ypos = uint16(ypos)
+	t.follow = false
+	t.RequestRedraw()
+}
+
+func (t *TextView) ScrollHome() {
+	t.ypos = 0
+	t.follow = false
+	t.RequestRedraw()
+}
+
+func (t *TextView) ScrollEnd() {
+	t.follow = true
+	t.gotoEnd()
 	t.RequestRedraw()
 }
 
+// Lines scrolled by each turn of the mouse wheel
+const WheelLines = 3
+
+func (t *TextView) HandleMouse(mouse input.MouseInput) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for i := 0; i < WheelLines; i++ {
+		switch mouse.Button {
+		case input.MouseWheelUp:
+			t.ScrollBack()
+		case input.MouseWheelDown:
+			t.ScrollFwd()
+		}
+	}
+}
+
+// Keys text views handle themselves, for scrolling and searching
+var TextViewKeys = []input.KeyInput{
+	input.KeyInputKey(keyboard.KeyArrowUp), input.KeyInputKey(keyboard.KeyArrowDown),
+	input.KeyInputKey(keyboard.KeyArrowLeft), input.KeyInputKey(keyboard.KeyArrowRight),
+	input.KeyInputKey(keyboard.KeyPgup), input.KeyInputKey(keyboard.KeyPgdn),
+	input.KeyInputKey(keyboard.KeyHome), input.KeyInputKey(keyboard.KeyEnd),
+	input.KeyInputChar('/'), input.KeyInputChar('?'), input.KeyInputChar('n'), input.KeyInputChar('N'),
+}
+
+func (t *TextView) HandleSearchKeys(input input.KeyInput) bool {
+	switch input.GetChar() {
+	case '/':
+		t.StartSearch(false)
+	case '?':
+		t.StartSearch(true)
+	case 'n':
+		t.NextMatch(false)
+	case 'N':
+		t.NextMatch(true)
+	default:
+		return false
+	}
+	return true
+}
+
 func (t *TextView) HandleInput(input input.KeyInput) {
+	t.mutex.Lock()
+
+	if t.search.editing {
+		t.HandleSearchInput(input)
+		t.mutex.Unlock()
+		return
+	}
 
 	switch input.GetKey() {
 	case keyboard.KeyArrowDown:
@@ -131,8 +398,16 @@ func (t *TextView) HandleInput(input input.KeyInput) {
 		t.ScrollLeft()
 	case keyboard.KeyArrowRight:
 		t.ScrollRight()
+	case keyboard.KeyHome:
+		t.ScrollHome()
+	case keyboard.KeyEnd:
+		t.ScrollEnd()
 	default:
-		t.ViewImpl.HandleInput(input)
+		if !t.HandleSearchKeys(input) {
+			t.mutex.Unlock()
+			t.ViewImpl.HandleInput(input)
+			return
+		}
 	}
-
+	t.mutex.Unlock()
 }
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/clidockermgr/input"
+	"github.com/eiannone/keyboard"
+)
+
+/**
+	Previously entered values, can be shared by several inputs
+**/
+type InputHistory struct {
+	entries []string
+}
+
+func InputHistoryNew() *InputHistory {
+	return &InputHistory{}
+}
+
+// Adds an entry as the most recent one, removing duplicates
+func (h *InputHistory) Add(entry string) {
+	if entry == "" {
+		return
+	}
+	for i := range h.entries {
+		if h.entries[i] == entry {
+			h.entries = append(h.entries[0:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, entry)
+}
+
+/**
+	A single line text input. When it has a history, up and
+	down arrows go through the previously entered values.
+**/
+type TextInput struct {
+	ViewImpl
+	text     []rune
+	cursor   int
+	offset   int
+	history  *InputHistory
+	position int
+	draft    string
+}
+
+func TextInputNew(text string) *TextInput {
+	var textInput = TextInput{}
+	textInput.Init()
+	textInput.SetText(text)
+	return &textInput
+}
+
+func (t *TextInput) SetHistory(history *InputHistory) {
+	t.history = history
+	t.position = len(history.entries)
+}
+
+// Stores the current text in the history
+func (t *TextInput) Commit() {
+	if t.history != nil {
+		t.history.Add(t.Text())
+		t.position = len(t.history.entries)
+	}
+}
+
+func (t *TextInput) FormValue() interface{} {
+	return t.Text()
+}
+
+func (t *TextInput) HistoryBack() {
+	if t.history == nil || t.position == 0 {
+		return
+	}
+	if t.position == len(t.history.entries) {
+		t.draft = t.Text()
+	}
+	t.position--
+	t.SetText(t.history.entries[t.position])
+}
+
+func (t *TextInput) HistoryFwd() {
+	if t.history == nil || t.position >= len(t.history.entries) {
+		return
+	}
+	t.position++
+	if t.position == len(t.history.entries) {
+		t.SetText(t.draft)
+	} else {
+		t.SetText(t.history.entries[t.position])
+	}
+}
+
+func (t *TextInput) Text() string {
+	return string(t.text)
+}
+
+func (t *TextInput) SetText(text string) {
+	t.text = []rune(text)
+	t.cursor = len(t.text)
+	t.offset = 0
+	t.RequestRedraw()
+}
+
+func (t *TextInput) Insert(char rune) {
+	t.text = append(t.text[0:t.cursor], append([]rune{char}, t.text[t.cursor:]...)...)
+	t.cursor++
+}
+
+func (t *TextInput) HandleInput(key input.KeyInput) {
+	switch key.GetKey() {
+	case keyboard.KeyArrowUp:
+		t.HistoryBack()
+	case keyboard.KeyArrowDown:
+		t.HistoryFwd()
+	case keyboard.KeyArrowLeft:
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case keyboard.KeyArrowRight:
+		if t.cursor < len(t.text) {
+			t.cursor++
+		}
+	case keyboard.KeyHome, keyboard.KeyCtrlA:
+		t.cursor = 0
+	case keyboard.KeyEnd, keyboard.KeyCtrlE:
+		t.cursor = len(t.text)
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		if t.cursor > 0 {
+			t.text = append(t.text[0:t.cursor-1], t.text[t.cursor:]...)
+			t.cursor--
+		}
+	case keyboard.KeyDelete:
+		if t.cursor < len(t.text) {
+			t.text = append(t.text[0:t.cursor], t.text[t.cursor+1:]...)
+		}
+	case keyboard.KeyCtrlU:
+		t.text = t.text[t.cursor:]
+		t.cursor = 0
+	case keyboard.KeySpace:
+		t.Insert(' ')
+	default:
+		if key.GetChar() != 0 {
+			t.Insert(key.GetChar())
+		} else {
+			t.ViewImpl.HandleInput(key)
+			return
+		}
+	}
+	t.RequestRedraw()
+}
+
+func (t *TextInput) Draw() {
+	var width = int(t.rect.w)
+
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+width {
+		t.offset = t.cursor - width + 1
+	}
+
+	GotoXY(t.rect.x, t.rect.y)
+	CurrentTheme.Input.Apply()
+
+	for i := t.offset; i < t.offset+width; i++ {
+		var char = ' '
+		if i < len(t.text) {
+			char = t.text[i]
+		}
+		if i == t.cursor && t.focused {
+			CurrentTheme.Cursor.Apply()
+			fmt.Print(string(char))
+			Reset()
+			CurrentTheme.Input.Apply()
+		} else {
+			fmt.Print(string(char))
+		}
+	}
+	Reset()
+}
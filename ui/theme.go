@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+/**
+	Colors and attributes used to draw some part of the screen
+**/
+type Style struct {
+	Fg        Color `yaml:"fg"`
+	Bg        Color `yaml:"bg"`
+	Bold      bool  `yaml:"bold"`
+	Underline bool  `yaml:"underline"`
+	Reverse   bool  `yaml:"reverse"`
+}
+
+/**
+	Writes the style, in the current color mode, callers
+	should call Reset once done
+**/
+func (s Style) Apply() {
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if s.Reverse {
+		codes = append(codes, "7")
+	}
+	if code := s.Fg.code(false, CurrentColorMode); code != "" {
+		codes = append(codes, code)
+	}
+	if code := s.Bg.code(true, CurrentColorMode); code != "" {
+		codes = append(codes, code)
+	}
+	if len(codes) > 0 {
+		fmt.Printf("\u001b[%sm", strings.Join(codes, ";"))
+	}
+}
+
+/**
+	The styles used to draw the application
+**/
+type Theme struct {
+	Name string `yaml:"name"`
+	// Starting theme for theme files, which only need to set what they change
+	Base string `yaml:"base"`
+	// Titles of panes, while focused or not
+	Header        Style `yaml:"header"`
+	FocusedHeader Style `yaml:"focused-header"`
+	// Contents of panes, while focused or not
+	Pane        Style `yaml:"pane"`
+	FocusedPane Style `yaml:"focused-pane"`
+	// Borders and titles of popups
+	Border    Style `yaml:"border"`
+	Selection Style `yaml:"selection"`
+	Status    Style `yaml:"status"`
+	Error     Style `yaml:"error"`
+	// Stderr lines in logs
+	Stderr Style `yaml:"stderr"`
+	// Build steps
+	Step         Style `yaml:"step"`
+	Match        Style `yaml:"match"`
+	CurrentMatch Style `yaml:"current-match"`
+	// Text inputs, the cursor in them, and focused checkboxes, dropdowns and buttons
+	Input    Style `yaml:"input"`
+	Cursor   Style `yaml:"cursor"`
+	Control  Style `yaml:"control"`
+	Dropdown Style `yaml:"dropdown"`
+	// Charts of the container dashboard
+	Chart Style `yaml:"chart"`
+}
+
+var DarkTheme = Theme{
+	Name:          "dark",
+	Header:        Style{Fg: IndexedColor(0), Bg: IndexedColor(7)},
+	FocusedHeader: Style{Fg: IndexedColor(0), Bg: IndexedColor(6), Bold: true},
+	Selection:     Style{Underline: true},
+	Error:         Style{Fg: IndexedColor(1), Bold: true},
+	Stderr:        Style{Fg: IndexedColor(1)},
+	Step:          Style{Bold: true},
+	Match:         Style{Fg: IndexedColor(0), Bg: IndexedColor(3)},
+	CurrentMatch:  Style{Fg: IndexedColor(0), Bg: IndexedColor(2)},
+	Input:         Style{Underline: true},
+	Cursor:        Style{Reverse: true},
+	Control:       Style{Reverse: true},
+	Dropdown:      Style{Fg: IndexedColor(0), Bg: IndexedColor(7)},
+	Chart:         Style{Fg: IndexedColor(2)},
+}
+
+var LightTheme = Theme{
+	Name:          "light",
+	Header:        Style{Fg: IndexedColor(0), Bg: IndexedColor(252)},
+	FocusedHeader: Style{Fg: IndexedColor(15), Bg: IndexedColor(25), Bold: true},
+	Border:        Style{Fg: IndexedColor(25)},
+	Selection:     Style{Bg: IndexedColor(153)},
+	Status:        Style{Fg: IndexedColor(238)},
+	Error:         Style{Fg: IndexedColor(124), Bold: true},
+	Stderr:        Style{Fg: IndexedColor(124)},
+	Step:          Style{Fg: IndexedColor(25), Bold: true},
+	Match:         Style{Bg: IndexedColor(229)},
+	CurrentMatch:  Style{Bg: IndexedColor(214)},
+	Input:         Style{Underline: true},
+	Cursor:        Style{Reverse: true},
+	Control:       Style{Fg: IndexedColor(15), Bg: IndexedColor(25)},
+	Dropdown:      Style{Fg: IndexedColor(0), Bg: IndexedColor(252)},
+	Chart:         Style{Fg: IndexedColor(25)},
+}
+
+var HighContrastTheme = Theme{
+	Name:          "high-contrast",
+	Header:        Style{Fg: IndexedColor(15), Bg: IndexedColor(0), Bold: true, Underline: true},
+	FocusedHeader: Style{Fg: IndexedColor(0), Bg: IndexedColor(11), Bold: true},
+	Pane:          Style{Fg: IndexedColor(7), Bg: IndexedColor(0)},
+	FocusedPane:   Style{Fg: IndexedColor(15), Bg: IndexedColor(0)},
+	Border:        Style{Fg: IndexedColor(11), Bold: true},
+	Selection:     Style{Reverse: true, Bold: true},
+	Status:        Style{Fg: IndexedColor(15), Bold: true},
+	Error:         Style{Fg: IndexedColor(15), Bg: IndexedColor(9), Bold: true},
+	Stderr:        Style{Fg: IndexedColor(9), Bold: true},
+	Step:          Style{Fg: IndexedColor(11), Bold: true},
+	Match:         Style{Fg: IndexedColor(0), Bg: IndexedColor(14)},
+	CurrentMatch:  Style{Fg: IndexedColor(0), Bg: IndexedColor(11), Bold: true},
+	Input:         Style{Underline: true, Bold: true},
+	Cursor:        Style{Reverse: true},
+	Control:       Style{Fg: IndexedColor(0), Bg: IndexedColor(11), Bold: true},
+	Dropdown:      Style{Fg: IndexedColor(15), Bg: IndexedColor(0), Reverse: true},
+	Chart:         Style{Fg: IndexedColor(11), Bg: IndexedColor(0)},
+}
+
+var BuiltinThemes = map[string]*Theme{
+	DarkTheme.Name:         &DarkTheme,
+	LightTheme.Name:        &LightTheme,
+	HighContrastTheme.Name: &HighContrastTheme,
+}
+
+// The theme used to draw
+var CurrentTheme = &DarkTheme
+
+func SetTheme(theme *Theme) {
+	CurrentTheme = theme
+}
+
+func BuiltinThemeNames() []string {
+	var names []string
+	for name := range BuiltinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/**
+	Returns a built-in theme by name, or else loads a theme file
+**/
+func FindTheme(nameOrPath string) (*Theme, error) {
+	if theme, ok := BuiltinThemes[nameOrPath]; ok {
+		return theme, nil
+	}
+	return LoadTheme(nameOrPath)
+}
+
+/**
+	Loads a theme file, styles not in the file are taken from
+	the theme named in "base", dark if not given.
+**/
+func LoadTheme(path string) (*Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		Base string `yaml:"base"`
+	}
+	if err = yaml.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var base = &DarkTheme
+	if header.Base != "" {
+		var ok bool
+		if base, ok = BuiltinThemes[header.Base]; !ok {
+			return nil, fmt.Errorf("unknown base theme '%s', use one of %s",
+				header.Base, strings.Join(BuiltinThemeNames(), ", "))
+		}
+	}
+
+	var theme = *base
+	theme.Name = path
+	if err = yaml.UnmarshalStrict(data, &theme); err != nil {
+		return nil, err
+	}
+	return &theme, nil
+}
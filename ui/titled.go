@@ -5,22 +5,28 @@ import (
 	"strings"
 
 	"github.com/clidockermgr/input"
+	"github.com/clidockermgr/util"
 )
 
-type BorderStyle func(string, Rect)
+type BorderStyle func(title string, rect Rect, focused bool)
 
-func HeaderBorder(title string, rect Rect) {
+func headerStyle(focused bool) Style {
+	if focused {
+		return CurrentTheme.FocusedHeader
+	}
+	return CurrentTheme.Header
+}
+
+func HeaderBorder(title string, rect Rect, focused bool) {
 	GotoXY(rect.x, rect.y)
-	Background(7)
-	Foreground(0)
+	headerStyle(focused).Apply()
 	WriteFill(title, rect.w)
 	Reset()
 }
 
-func FullBorder(title string, rect Rect) {
+func FullBorder(title string, rect Rect, focused bool) {
 	GotoXY(rect.x, rect.y)
-	Background(7)
-	Foreground(0)
+	headerStyle(focused).Apply()
 	WriteFill(title, rect.w)
 	GotoXY(rect.x, rect.y+rect.h-1)
 	WriteFill("", rect.w)
@@ -29,7 +35,8 @@ func FullBorder(title string, rect Rect) {
 	Reset()
 }
 
-func LineBorder(title string, rect Rect) {
+func LineBorder(title string, rect Rect, focused bool) {
+	CurrentTheme.Border.Apply()
 	GotoXY(rect.x+1, rect.y)
 	fmt.Print(title)
 	GotoXY(rect.x, rect.y)
@@ -46,6 +53,7 @@ func LineBorder(title string, rect Rect) {
 	fmt.Print(strings.Repeat(LineBorderVertical, int(rect.w-2)))
 	GotoXY(rect.x+1+uint16(len(title)), rect.y)
 	fmt.Print(strings.Repeat(LineBorderVertical, int(rect.w-2)-len(title)))
+	Reset()
 }
 
 /**
@@ -64,6 +72,11 @@ func TitledContainerNew(title string, child View, border bool) *TitledContainer
 	return &container
 }
 
+func (t *TitledContainer) SetTitle(title string) {
+	t.title = title
+	t.RequestRedraw()
+}
+
 func (t *TitledContainer) SetRect(rect Rect) {
 	t.ViewImpl.SetRect(rect)
 
@@ -77,13 +90,13 @@ func (t *TitledContainer) SetRect(rect Rect) {
 		Rect{
 			x: rect.x + padding,
 			y: rect.y + 1,
-			w: rect.w - (padding * 2),
-			h: rect.h - 1 - padding})
+			w: uint16(util.Max(int(rect.w)-int(padding*2), 0)),
+			h: uint16(util.Max(int(rect.h)-1-int(padding), 0))})
 }
 
 func (t *TitledContainer) Draw() {
 	if t.Border != nil {
-		t.Border(t.title, t.rect)
+		t.Border(t.title, t.rect, t.focused)
 	}
 	t.child.Draw()
 }
@@ -100,10 +113,19 @@ func (t *TitledContainer) HandleInput(input input.KeyInput) {
 	t.child.HandleInput(input)
 }
 
+func (t *TitledContainer) HandleMouse(mouse input.MouseInput) {
+	HandleMouse(t.child, mouse)
+}
+
 func (t *TitledContainer) SetFocused(focused bool) {
+	t.ViewImpl.SetFocused(focused)
 	t.child.SetFocused(focused)
 }
 
 func (t TitledContainer) IsFocusable() bool {
 	return t.child.IsFocusable()
 }
+
+func (t *TitledContainer) CapturesInput() bool {
+	return CapturesInput(t.child)
+}
@@ -18,6 +18,34 @@ type View interface {
 	Draw()
 	CheckRedrawFlag() bool
 	RequestRedraw()
+	Contains(x, y uint16) bool
+}
+
+/**
+	Implemented by views which, while editing, need to receive
+	keys the application would otherwise handle, like ESC or TAB
+**/
+type InputCapturer interface {
+	CapturesInput() bool
+}
+
+func CapturesInput(view View) bool {
+	capturer, ok := view.(InputCapturer)
+	return ok && capturer.CapturesInput()
+}
+
+/**
+	Implemented by views which react to the mouse, the
+	event is only sent when the mouse is over the view
+**/
+type MouseHandler interface {
+	HandleMouse(mouse input.MouseInput)
+}
+
+func HandleMouse(view View, mouse input.MouseInput) {
+	if handler, ok := view.(MouseHandler); ok && view.Contains(mouse.X, mouse.Y) {
+		handler.HandleMouse(mouse)
+	}
 }
 
 /**
@@ -42,6 +70,11 @@ func (v *ViewImpl) SetRect(rect Rect) {
 	v.RequestRedraw()
 }
 
+func (v *ViewImpl) Contains(x, y uint16) bool {
+	return x >= v.rect.x && x < v.rect.x+v.rect.w &&
+		y >= v.rect.y && y < v.rect.y+v.rect.h
+}
+
 func (v *ViewImpl) SetFocusable(focusable bool) {
 	v.focusable = focusable
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 )
@@ -30,6 +31,13 @@ func Min(v1, v2 int) int {
 	return v2
 }
 
+func Min64(v1, v2 int64) int64 {
+	if v1 < v2 {
+		return v1
+	}
+	return v2
+}
+
 const KB = 1024
 const MB = KB * 1024
 const GB = MB * 1024
@@ -51,6 +59,22 @@ func FormatMemory(amount uint64) string {
 	return fmt.Sprintf("%.2f TB", float32(amount)/TB)
 }
 
+func FormatRate(bytesPerSecond float64) string {
+	return FormatMemory(uint64(bytesPerSecond)) + "/s"
+}
+
+func FormatProgressBar(current, total int64, width int) string {
+	if total <= 0 {
+		return "[" + strings.Repeat(" ", width) + "]"
+	}
+	var done = int(int64(width) * Min64(current, total) / total)
+	var bar = strings.Repeat("=", done)
+	if done < width {
+		bar += ">" + strings.Repeat(" ", width-done-1)
+	}
+	return "[" + bar + "]"
+}
+
 func ParseStats(statsBody []byte) *types.Stats {
 	var stats types.Stats
 	json.Unmarshal(statsBody, &stats)
@@ -62,3 +86,26 @@ func ParseStatsBody(body io.ReadCloser) *types.Stats {
 	var result, _ = buf.ReadBytes(byte(0))
 	return ParseStats(result)
 }
+
+/**
+	CPU usage between the previous and the current sample, as
+	the docker CLI computes it: 100% is one CPU fully used.
+**/
+func CPUPercent(stats *types.Stats) float64 {
+	var cpuDelta = float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	var systemDelta = float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+
+	var onlineCPUs = float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+func FormatPercent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}